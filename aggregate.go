@@ -0,0 +1,148 @@
+package redblack
+
+import "iter"
+
+// Monoid describes how the items of an AggregateTree are aggregated.
+// Measure maps a single item to an aggregate and Combine merges the aggregates of two adjacent key ranges,
+// the smaller keys first. Combine has to be associative and Identity has to be its neutral element,
+// e.g. 0 and + for sums or the smallest possible value and max for maximums.
+type Monoid[T, A any] struct {
+	Identity A
+	Measure  func(item T) A
+	Combine  func(a, b A) A
+}
+
+// aggregator is implemented by items that keep the aggregate of their subtree. Node.update calls it
+// whenever the subtree of a node changes, if the owner of the node was created for such items.
+type aggregator[V any, T Orderable[V]] interface {
+	aggregate(left, right *Node[V, T]) T
+}
+
+// aggItem stores an item together with the aggregate of the subtree of its node.
+// Entries are ordered by the key of the item only.
+type aggItem[V any, T Orderable[V], A any] struct {
+	item T
+	agg  A
+	m    *Monoid[T, A]
+}
+
+func (e aggItem[V, T, A]) CompareTo(other V) int {
+	return e.item.CompareTo(other)
+}
+
+func (e aggItem[V, T, A]) Value() V {
+	return e.item.Value()
+}
+
+func (e aggItem[V, T, A]) aggregate(left, right *Node[V, aggItem[V, T, A]]) aggItem[V, T, A] {
+	e.agg = e.m.Measure(e.item)
+	if left != nil {
+		e.agg = e.m.Combine(left.value.agg, e.agg)
+	}
+	if right != nil {
+		e.agg = e.m.Combine(e.agg, right.value.agg)
+	}
+	return e
+}
+
+// AggregateTree is a red-black tree in which every node keeps the aggregate of the items in its subtree.
+// This allows aggregating any key range in O(log n).
+// Create trees with NewAggregateTree, the zero value has no monoid.
+type AggregateTree[V any, T Orderable[V], A any] struct {
+	tree Tree[V, aggItem[V, T, A]]
+	m    *Monoid[T, A]
+}
+
+// NewAggregateTree creates a new empty tree that aggregates its items with m.
+func NewAggregateTree[V any, T Orderable[V], A any](m Monoid[T, A]) *AggregateTree[V, T, A] {
+	return &AggregateTree[V, T, A]{m: &m}
+}
+
+// Aggregate returns the aggregate of the items with keys k with lo <= k < hi in O(log n).
+// Returns the identity of the monoid if there are no such keys.
+func (t *AggregateTree[V, T, A]) Aggregate(lo, hi V) A {
+	return t.aggregate(t.tree.root, bound[V]{key: lo, inclusive: true}, bound[V]{key: hi})
+}
+
+// AggregateAll returns the aggregate of all items in the tree in O(1).
+func (t *AggregateTree[V, T, A]) AggregateAll() A {
+	if t.tree.root == nil {
+		return t.m.Identity
+	}
+	return t.tree.root.value.agg
+}
+
+// aggregate returns the aggregate of the items in the subtree of n with keys between lo and hi.
+// Below the node where the paths to lo and hi part, only one of the bounds is left, so that
+// at most two paths are visited.
+func (t *AggregateTree[V, T, A]) aggregate(n *Node[V, aggItem[V, T, A]], lo, hi bound[V]) A {
+	if n == nil {
+		return t.m.Identity
+	}
+	if lo.unbounded && hi.unbounded {
+		return n.value.agg
+	}
+	if !lo.unbounded && !lo.allows(n.value.CompareTo(lo.key)) {
+		return t.aggregate(n.right, lo, hi)
+	}
+	if !hi.unbounded && !hi.allows(-n.value.CompareTo(hi.key)) {
+		return t.aggregate(n.left, lo, hi)
+	}
+	agg := t.m.Combine(t.aggregate(n.left, lo, bound[V]{unbounded: true}), t.m.Measure(n.value.item))
+	return t.m.Combine(agg, t.aggregate(n.right, bound[V]{unbounded: true}, hi))
+}
+
+// Search returns true if the key is found in the tree and the value of the key.
+// If the key is not found, the second return value is the key itself.
+func (t *AggregateTree[V, T, A]) Search(k V) (bool, V) {
+	return t.tree.Search(k)
+}
+
+// Insert adds the item to the tree and updates the aggregates on its path.
+// Returns KeyExistsError if the key already exists in the tree.
+func (t *AggregateTree[V, T, A]) Insert(item T) error {
+	return t.tree.Insert(aggItem[V, T, A]{item: item, m: t.m})
+}
+
+// Delete removes the key from the tree and updates the aggregates on its path.
+// Returns false if the key is not found.
+func (t *AggregateTree[V, T, A]) Delete(k V) bool {
+	return t.tree.Delete(k)
+}
+
+// Len returns the number of nodes in the tree.
+func (t *AggregateTree[V, T, A]) Len() int {
+	return t.tree.Len()
+}
+
+// Min returns the smallest key in the tree, the zero value for an empty tree.
+func (t *AggregateTree[V, T, A]) Min() V {
+	return t.tree.Min()
+}
+
+// MinOk returns the smallest key in the tree.
+// Returns false if the tree is empty.
+func (t *AggregateTree[V, T, A]) MinOk() (V, bool) {
+	return t.tree.MinOk()
+}
+
+// Max returns the largest key in the tree, the zero value for an empty tree.
+func (t *AggregateTree[V, T, A]) Max() V {
+	return t.tree.Max()
+}
+
+// MaxOk returns the largest key in the tree.
+// Returns false if the tree is empty.
+func (t *AggregateTree[V, T, A]) MaxOk() (V, bool) {
+	return t.tree.MaxOk()
+}
+
+// Sorted returns an iterator that yields the keys in the tree in sorted order.
+func (t *AggregateTree[V, T, A]) Sorted() iter.Seq[V] {
+	return t.tree.Sorted()
+}
+
+// Range returns an iterator that yields the keys k with lo <= k < hi in sorted order.
+func (t *AggregateTree[V, T, A]) Range(lo, hi V) iter.Seq[V] {
+	return t.tree.Range(lo, hi)
+}
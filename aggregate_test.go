@@ -0,0 +1,122 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func sumMonoid() redblack.Monoid[redblack.Orderable[int], int] {
+	return redblack.Monoid[redblack.Orderable[int], int]{
+		Identity: 0,
+		Measure:  func(item redblack.Orderable[int]) int { return item.Value() },
+		Combine:  func(a, b int) int { return a + b },
+	}
+}
+
+func TestAggregateTree_Aggregate(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		lo, hi int
+		want   int
+	}{
+		{"Empty Tree", []int{}, 0, 10, 0},
+		{"All", []int{1, 2, 3, 4}, 0, 10, 10},
+		{"Half-Open", []int{1, 2, 3, 4}, 2, 4, 5},
+		{"Empty Range", []int{1, 2, 3, 4}, 3, 3, 0},
+		{"Reversed Range", []int{1, 2, 3, 4}, 4, 1, 0},
+		{"Below Min", []int{5, 6, 7}, 0, 5, 0},
+		{"Sparse", []int{10, 20, 30, 40, 50}, 15, 45, 90},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := redblack.NewAggregateTree[int](sumMonoid())
+			for _, i := range rand.Perm(len(tt.values)) {
+				if err := t.Insert(redblack.Ordered(tt.values[i])); err != nil {
+					t1.Fatalf("Insert() error = %v", err)
+				}
+			}
+			if got := t.Aggregate(tt.lo, tt.hi); got != tt.want {
+				t1.Errorf("Aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateTree_RandomOperations(t1 *testing.T) {
+	// concatenation is not commutative, so this also checks that aggregates are combined in key order
+	t := redblack.NewAggregateTree[int](redblack.Monoid[payload, string]{
+		Identity: "",
+		Measure:  func(p payload) string { return p.data },
+		Combine:  func(a, b string) string { return a + b },
+	})
+	keys := map[int]bool{}
+	for i := 0; i < 3000; i++ {
+		k := rand.Intn(100)
+		if keys[k] {
+			t.Delete(k)
+			delete(keys, k)
+		} else {
+			if err := t.Insert(payload{k, strconv.Itoa(k) + ","}); err != nil {
+				t1.Fatalf("Insert() error = %v", err)
+			}
+			keys[k] = true
+		}
+
+		lo, hi := rand.Intn(110)-5, rand.Intn(110)-5
+		want := ""
+		for j := lo; j < hi; j++ {
+			if keys[j] {
+				want += strconv.Itoa(j) + ","
+			}
+		}
+		if got := t.Aggregate(lo, hi); got != want {
+			t1.Fatalf("Aggregate(%d, %d) = %q, want %q", lo, hi, got, want)
+		}
+	}
+	want := ""
+	for k := range t.Sorted() {
+		want += strconv.Itoa(k) + ","
+	}
+	if got := t.AggregateAll(); got != want {
+		t1.Errorf("AggregateAll() = %q, want %q", got, want)
+	}
+}
+
+func TestAggregateTree_MaxInWindow(t1 *testing.T) {
+	// latencies by timestamp, the maximum in a sliding window is queried
+	latencies := rand.Perm(1000)
+	t := redblack.NewAggregateTree[int](redblack.Monoid[payload, int]{
+		Identity: -1,
+		Measure:  func(p payload) int { v, _ := strconv.Atoi(p.data); return v },
+		Combine:  func(a, b int) int { return max(a, b) },
+	})
+	for ts, l := range latencies {
+		if err := t.Insert(payload{ts, strconv.Itoa(l)}); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	for ts := 0; ts < len(latencies); ts += 37 {
+		want := -1
+		for _, l := range latencies[max(ts-50, 0):ts] {
+			want = max(want, l)
+		}
+		if got := t.Aggregate(ts-50, ts); got != want {
+			t1.Errorf("Aggregate(%d, %d) = %v, want %v", ts-50, ts, got, want)
+		}
+	}
+}
+
+func BenchmarkAggregateTree_Insert(b *testing.B) {
+	keys := rand.Perm(1 << 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := redblack.NewAggregateTree[int](sumMonoid())
+		for _, k := range keys {
+			t.Insert(redblack.Ordered(k))
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package redblack
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"reflect"
+)
+
+// The binary format starts with a header of 17 bytes:
+//
+//	magic    [4]byte  "RBTK"
+//	version  uint8
+//	count    uint64   big endian, number of keys
+//	checksum uint32   big endian, CRC-32 (Castagnoli) of all key records
+//
+// The header is followed by one record per key in ascending order. A record is the length of the encoded
+// key as unsigned varint, followed by the encoded key.
+const (
+	binaryMagic   = "RBTK"
+	binaryVersion = 1
+	headerSize    = len(binaryMagic) + 1 + 8 + 4
+	// maxKeySize limits the memory that a corrupted length prefix can allocate
+	maxKeySize = 1 << 30
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// formatError is returned when a tree cannot be encoded or decoded.
+type formatError string
+
+func (e formatError) Error() string {
+	return string(e)
+}
+
+const (
+	NoKeyCodecError         = formatError("Keys of this type cannot be encoded without a codec.")
+	NoItemConstructorError  = formatError("Items cannot be created from keys of this type.")
+	InvalidFormatError      = formatError("Data is not a serialized tree.")
+	UnsupportedVersionError = formatError("Unsupported version of the serialization format.")
+	ChecksumError           = formatError("Checksum does not match the data.")
+)
+
+// KeyCodec converts keys to bytes and back for WriteToCodec and ReadFromCodec.
+type KeyCodec[V any] interface {
+	// AppendKey appends the encoding of k to buf and returns the extended buffer.
+	AppendKey(buf []byte, k V) ([]byte, error)
+	// DecodeKey decodes a key that has been encoded by AppendKey. data must not be retained after returning.
+	DecodeKey(data []byte) (V, error)
+}
+
+// KindCodec is the default KeyCodec. It encodes keys whose underlying type is an integer, float or string type.
+// Integers are encoded as varints, floats by their IEEE 754 bits and strings by their bytes.
+type KindCodec[V any] struct{}
+
+func (KindCodec[V]) AppendKey(buf []byte, k V) ([]byte, error) {
+	v := reflect.ValueOf(&k).Elem()
+	switch {
+	case v.CanInt():
+		return binary.AppendVarint(buf, v.Int()), nil
+	case v.CanUint():
+		return binary.AppendUvarint(buf, v.Uint()), nil
+	case v.CanFloat():
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(v.Float())), nil
+	case v.Kind() == reflect.String:
+		return append(buf, v.String()...), nil
+	}
+	return buf, NoKeyCodecError
+}
+
+func (KindCodec[V]) DecodeKey(data []byte) (k V, err error) {
+	v := reflect.ValueOf(&k).Elem()
+	switch {
+	case v.CanInt():
+		x, n := binary.Varint(data)
+		if n != len(data) || v.OverflowInt(x) {
+			return k, InvalidFormatError
+		}
+		v.SetInt(x)
+	case v.CanUint():
+		x, n := binary.Uvarint(data)
+		if n != len(data) || v.OverflowUint(x) {
+			return k, InvalidFormatError
+		}
+		v.SetUint(x)
+	case v.CanFloat():
+		if len(data) != 8 {
+			return k, InvalidFormatError
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+	case v.Kind() == reflect.String:
+		v.SetString(string(data))
+	default:
+		return k, NoKeyCodecError
+	}
+	return k, nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// WriteTo writes the keys of the tree in the binary format to w and returns the number of bytes written.
+// Keys whose underlying type is an integer, float or string type are supported, for all other keys use
+// WriteToCodec.
+func (t *Tree[V, T]) WriteTo(w io.Writer) (int64, error) {
+	return t.WriteToCodec(w, KindCodec[V]{})
+}
+
+// WriteToCodec writes the keys of the tree in the binary format to w, using c to encode the keys.
+// Returns the number of bytes written. The keys are encoded twice, once for the checksum in the header
+// and once for writing them, so that only a single key is buffered at a time.
+func (t *Tree[V, T]) WriteToCodec(w io.Writer, c KeyCodec[V]) (int64, error) {
+	var lenBuf [binary.MaxVarintLen64]byte
+	var keyBuf []byte
+	var err error
+
+	crc := uint32(0)
+	for k := range t.Sorted() {
+		if keyBuf, err = c.AppendKey(keyBuf[:0], k); err != nil {
+			return 0, err
+		}
+		crc = crc32.Update(crc, crcTable, lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(keyBuf)))])
+		crc = crc32.Update(crc, crcTable, keyBuf)
+	}
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	header := make([]byte, 0, headerSize)
+	header = append(header, binaryMagic...)
+	header = append(header, binaryVersion)
+	header = binary.BigEndian.AppendUint64(header, uint64(t.num))
+	header = binary.BigEndian.AppendUint32(header, crc)
+	bw.Write(header)
+	for k := range t.Sorted() {
+		// the keys have been encoded successfully before, and bufio.Writer keeps the first write error
+		// and returns it from Flush
+		keyBuf, _ = c.AppendKey(keyBuf[:0], k)
+		bw.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(keyBuf)))])
+		bw.Write(keyBuf)
+	}
+	err = bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom replaces the contents of the tree with the keys read from r in the binary format and returns
+// the number of bytes read. Keys are decoded as in WriteTo and the items are created with Ordered as in
+// UnmarshalJSON; for all other keys and items use ReadFromCodec.
+func (t *Tree[V, T]) ReadFrom(r io.Reader) (int64, error) {
+	return t.ReadFromCodec(r, KindCodec[V]{}, newOrderedItem[V, T])
+}
+
+// ReadFromCodec replaces the contents of the tree with the keys read from r in the binary format, using c to
+// decode the keys and newItem to create the items. Returns the number of bytes read.
+//
+// r is read in a streaming fashion and the tree is built in O(n). The tree is only replaced if all keys have been
+// read and the checksum matches. r is read through a bufio.Reader, which may read past the end of the tree;
+// pass a *bufio.Reader to continue reading from it afterwards.
+// Returns InvalidFormatError, UnsupportedVersionError or ChecksumError if the data is not valid.
+func (t *Tree[V, T]) ReadFromCodec(r io.Reader, c KeyCodec[V], newItem func(k V) (T, error)) (int64, error) {
+	cr := &countingReader{r: bufio.NewReader(r)}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(cr, header); err != nil {
+		return cr.n, unexpectedEOF(err)
+	}
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return cr.n, InvalidFormatError
+	}
+	if header[len(binaryMagic)] != binaryVersion {
+		return cr.n, UnsupportedVersionError
+	}
+	count := binary.BigEndian.Uint64(header[len(binaryMagic)+1:])
+	checksum := binary.BigEndian.Uint32(header[len(binaryMagic)+9:])
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	var keyBuf []byte
+	crc := uint32(0)
+	// do not trust the count for preallocating, the data may be corrupted
+	items := make([]T, 0, min(count, 1<<16))
+	for i := uint64(0); i < count; i++ {
+		l, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return cr.n, unexpectedEOF(err)
+		}
+		if l > maxKeySize {
+			return cr.n, InvalidFormatError
+		}
+		if uint64(cap(keyBuf)) < l {
+			keyBuf = make([]byte, l)
+		}
+		keyBuf = keyBuf[:l]
+		if _, err := io.ReadFull(cr, keyBuf); err != nil {
+			return cr.n, unexpectedEOF(err)
+		}
+		crc = crc32.Update(crc, crcTable, lenBuf[:binary.PutUvarint(lenBuf[:], l)])
+		crc = crc32.Update(crc, crcTable, keyBuf)
+
+		k, err := c.DecodeKey(keyBuf)
+		if err != nil {
+			return cr.n, err
+		}
+		item, err := newItem(k)
+		if err != nil {
+			return cr.n, err
+		}
+		items = append(items, item)
+	}
+	if crc != checksum {
+		return cr.n, ChecksumError
+	}
+
+	tree, err := NewTreeFromSorted(items, false)
+	if err != nil {
+		return cr.n, err
+	}
+	*t = *tree
+	return cr.n, nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, since the data ended before the tree was complete.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+package redblack_test
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"io"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+// sensorID is a named key type, which Ordered cannot create items for.
+type sensorID uint16
+
+type sensor sensorID
+
+func (s sensor) CompareTo(other sensorID) int {
+	return cmp.Compare(sensorID(s), other)
+}
+
+func (s sensor) Value() sensorID {
+	return sensorID(s)
+}
+
+// pointItem orders points, which are structs, so they have no default codec.
+type pointItem point
+
+func (p pointItem) CompareTo(other point) int {
+	return comparePoints(point(p), other)
+}
+
+func (p pointItem) Value() point {
+	return point(p)
+}
+
+// decimalCodec encodes int keys as decimal strings.
+type decimalCodec struct{}
+
+func (decimalCodec) AppendKey(buf []byte, k int) ([]byte, error) {
+	return strconv.AppendInt(buf, int64(k), 10), nil
+}
+
+func (decimalCodec) DecodeKey(data []byte) (int, error) {
+	return strconv.Atoi(string(data))
+}
+
+func TestTree_WriteToReadFrom(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+	}{
+		{"Empty Tree", []int{}},
+		{"One Element", []int{1}},
+		{"Negative Keys", []int{-300, -1, 0, 1, 300}},
+		{"Many Elements", rand.Perm(10000)},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, tt.values)
+			var buf bytes.Buffer
+			n, err := t.WriteTo(&buf)
+			if err != nil {
+				t1.Fatalf("WriteTo() error = %v", err)
+			}
+			if n != int64(buf.Len()) {
+				t1.Errorf("WriteTo() = %v, want %v", n, buf.Len())
+			}
+
+			got := newIntTree(t1, []int{42})
+			m, err := got.ReadFrom(&buf)
+			if err != nil {
+				t1.Fatalf("ReadFrom() error = %v", err)
+			}
+			if m != n {
+				t1.Errorf("ReadFrom() = %v, want %v", m, n)
+			}
+			if !reflect.DeepEqual(got.ToSortedSlice(), t.ToSortedSlice()) {
+				t1.Errorf("ReadFrom() = %v, want %v", got.ToSortedSlice(), t.ToSortedSlice())
+			}
+			checkInvariants(t1, "ReadFrom()", got)
+		})
+	}
+}
+
+func TestTree_WriteToReadFromKeyTypes(t1 *testing.T) {
+	floats := new(redblack.Tree[float64, redblack.Orderable[float64]])
+	strs := new(redblack.Tree[string, redblack.Orderable[string]])
+	sensors := new(redblack.Tree[sensorID, sensor])
+	for i := 0; i < 100; i++ {
+		_ = floats.Insert(redblack.Ordered(float64(i) / 3))
+		_ = strs.Insert(redblack.Ordered(strconv.Itoa(i)))
+		_ = sensors.Insert(sensor(i * 600))
+	}
+
+	var buf bytes.Buffer
+	_, _ = floats.WriteTo(&buf)
+	gotFloats := new(redblack.Tree[float64, redblack.Orderable[float64]])
+	if _, err := gotFloats.ReadFrom(&buf); err != nil || !reflect.DeepEqual(gotFloats.ToSortedSlice(), floats.ToSortedSlice()) {
+		t1.Errorf("ReadFrom() = %v, %v, want %v", gotFloats.ToSortedSlice(), err, floats.ToSortedSlice())
+	}
+
+	buf.Reset()
+	_, _ = strs.WriteTo(&buf)
+	gotStrs := new(redblack.Tree[string, redblack.Orderable[string]])
+	if _, err := gotStrs.ReadFrom(&buf); err != nil || !reflect.DeepEqual(gotStrs.ToSortedSlice(), strs.ToSortedSlice()) {
+		t1.Errorf("ReadFrom() = %v, %v, want %v", gotStrs.ToSortedSlice(), err, strs.ToSortedSlice())
+	}
+
+	buf.Reset()
+	if _, err := sensors.WriteTo(&buf); err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+	data := buf.Bytes()
+	gotSensors := new(redblack.Tree[sensorID, sensor])
+	if _, err := gotSensors.ReadFrom(bytes.NewReader(data)); err != redblack.NoItemConstructorError {
+		t1.Errorf("ReadFrom() error = %v, want %v", err, redblack.NoItemConstructorError)
+	}
+	newSensor := func(k sensorID) (sensor, error) { return sensor(k), nil }
+	if _, err := gotSensors.ReadFromCodec(bytes.NewReader(data), redblack.KindCodec[sensorID]{}, newSensor); err != nil {
+		t1.Fatalf("ReadFromCodec() error = %v", err)
+	}
+	if !reflect.DeepEqual(gotSensors.ToSortedSlice(), sensors.ToSortedSlice()) {
+		t1.Errorf("ReadFromCodec() = %v, want %v", gotSensors.ToSortedSlice(), sensors.ToSortedSlice())
+	}
+
+	points := new(redblack.Tree[point, pointItem])
+	_ = points.Insert(pointItem{1, 2})
+	buf.Reset()
+	if _, err := points.WriteTo(&buf); err != redblack.NoKeyCodecError {
+		t1.Errorf("WriteTo() error = %v, want %v", err, redblack.NoKeyCodecError)
+	}
+}
+
+func TestTree_WriteToReadFromCodec(t1 *testing.T) {
+	t := new(redblack.Tree[int, payload])
+	for _, k := range rand.Perm(1000) {
+		_ = t.Insert(payload{key: k})
+	}
+	var buf bytes.Buffer
+	if _, err := t.WriteToCodec(&buf, decimalCodec{}); err != nil {
+		t1.Fatalf("WriteToCodec() error = %v", err)
+	}
+	// a second tree is written to the same stream and read after the first one
+	if _, err := newIntTree(t1, []int{1, 2, 3}).WriteTo(&buf); err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+
+	r := bufio.NewReader(&buf)
+	got := new(redblack.Tree[int, payload])
+	newPayload := func(k int) (payload, error) { return payload{key: k}, nil }
+	if _, err := got.ReadFromCodec(r, decimalCodec{}, newPayload); err != nil {
+		t1.Fatalf("ReadFromCodec() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.ToSortedSlice(), t.ToSortedSlice()) {
+		t1.Errorf("ReadFromCodec() = %v, want %v", got.ToSortedSlice(), t.ToSortedSlice())
+	}
+	second := new(intTree)
+	if _, err := second.ReadFrom(r); err != nil {
+		t1.Fatalf("ReadFrom() error = %v", err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(second.ToSortedSlice(), want) {
+		t1.Errorf("ReadFrom() = %v, want %v", second.ToSortedSlice(), want)
+	}
+}
+
+func TestTree_ReadFromInvalid(t1 *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newIntTree(t1, []int{1, 2, 3, 4, 5}).WriteTo(&buf); err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+	valid := buf.Bytes()
+	modified := func(i int, b byte) []byte {
+		data := bytes.Clone(valid)
+		data[i] = b
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"Empty", []byte{}, io.ErrUnexpectedEOF},
+		{"Short Header", valid[:10], io.ErrUnexpectedEOF},
+		{"Truncated", valid[:len(valid)-1], io.ErrUnexpectedEOF},
+		{"Magic", modified(0, 'X'), redblack.InvalidFormatError},
+		{"Version", modified(4, 2), redblack.UnsupportedVersionError},
+		{"Checksum", modified(13, valid[13]+1), redblack.ChecksumError},
+		{"Corrupted Key", modified(len(valid)-1, 8), redblack.ChecksumError},
+		{"Count Too Large", modified(12, valid[12]+1), io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, []int{42})
+			if _, err := t.ReadFrom(bytes.NewReader(tt.data)); err != tt.wantErr {
+				t1.Errorf("ReadFrom() error = %v, want %v", err, tt.wantErr)
+			}
+			// the tree is only replaced after successful reads
+			if want := []int{42}; !reflect.DeepEqual(t.ToSortedSlice(), want) {
+				t1.Errorf("tree = %v after failed ReadFrom(), want %v", t.ToSortedSlice(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package redblack
+
+// Cursor is a movable position in a tree. A new cursor is not positioned; call First, Last,
+// SeekGE or SeekLE before reading its key.
+//
+// A cursor remembers the key it points to instead of a node, so it stays usable when the tree
+// is modified: Next and Prev continue from the remembered key even if it has been deleted.
+// Every move costs O(log n).
+type Cursor[V any, T Orderable[V]] struct {
+	tree  *Tree[V, T]
+	key   V
+	valid bool
+}
+
+// Cursor returns a new cursor on the tree.
+func (t *Tree[V, T]) Cursor() *Cursor[V, T] {
+	return &Cursor[V, T]{tree: t}
+}
+
+// set moves the cursor to the node, or invalidates it if the node is nil.
+// Returns true if the cursor is valid.
+func (c *Cursor[V, T]) set(n *Node[V, T]) bool {
+	if n == nil {
+		var zero V
+		c.key, c.valid = zero, false
+		return false
+	}
+	c.key, c.valid = n.Value(), true
+	return true
+}
+
+// Valid returns true if the cursor points to a key.
+func (c *Cursor[V, T]) Valid() bool {
+	return c.valid
+}
+
+// Key returns the key the cursor points to, or the zero value if the cursor is not valid.
+func (c *Cursor[V, T]) Key() V {
+	return c.key
+}
+
+// First moves the cursor to the smallest key in the tree.
+// Returns false if the tree is empty.
+func (c *Cursor[V, T]) First() bool {
+	if c.tree.root == nil {
+		return c.set(nil)
+	}
+	return c.set(c.tree.root.min())
+}
+
+// Last moves the cursor to the largest key in the tree.
+// Returns false if the tree is empty.
+func (c *Cursor[V, T]) Last() bool {
+	if c.tree.root == nil {
+		return c.set(nil)
+	}
+	return c.set(c.tree.root.max())
+}
+
+// SeekGE moves the cursor to the smallest key in the tree that is greater than or equal to k.
+// Returns false if there is no such key.
+func (c *Cursor[V, T]) SeekGE(k V) bool {
+	return c.set(c.tree.root.searchUpper(k))
+}
+
+// SeekLE moves the cursor to the largest key in the tree that is less than or equal to k.
+// Returns false if there is no such key.
+func (c *Cursor[V, T]) SeekLE(k V) bool {
+	return c.set(c.tree.root.searchLower(k))
+}
+
+// Next moves the cursor to the next larger key.
+// Returns false if there is no larger key or the cursor is not valid.
+func (c *Cursor[V, T]) Next() bool {
+	if !c.valid {
+		return false
+	}
+	return c.set(c.tree.root.searchNext(c.key))
+}
+
+// Prev moves the cursor to the next smaller key.
+// Returns false if there is no smaller key or the cursor is not valid.
+func (c *Cursor[V, T]) Prev() bool {
+	if !c.valid {
+		return false
+	}
+	return c.set(c.tree.root.searchPrev(c.key))
+}
+
+// Pull returns the keys from the cursor position onwards in the style of iter.Pull.
+// Each call to next returns the key the cursor points to and advances the cursor to the next larger key.
+// Once the cursor is exhausted, next returns the zero value and false. Calling stop invalidates the cursor.
+func (c *Cursor[V, T]) Pull() (next func() (V, bool), stop func()) {
+	next = func() (V, bool) {
+		if !c.valid {
+			var zero V
+			return zero, false
+		}
+		k := c.key
+		c.Next()
+		return k, true
+	}
+	stop = func() {
+		c.set(nil)
+	}
+	return next, stop
+}
@@ -0,0 +1,150 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func newIntTree(t1 *testing.T, values []int) *redblack.Tree[int, redblack.Orderable[int]] {
+	t1.Helper()
+	vals := make([]redblack.Orderable[int], 0, len(values))
+	for _, v := range values {
+		vals = append(vals, redblack.Ordered(v))
+	}
+	rand.Shuffle(len(vals), func(i, j int) {
+		vals[i], vals[j] = vals[j], vals[i]
+	})
+	t, err := redblack.NewTree(vals, false)
+	if err != nil {
+		t1.Fatalf("redblack.NewTree() error = %v", err)
+	}
+	return t
+}
+
+func TestCursor_Seek(t1 *testing.T) {
+	type intCursor = redblack.Cursor[int, redblack.Orderable[int]]
+	values := []int{1, 2, 5, 8, 14, 23, 44, 50, 67}
+	tests := []struct {
+		name      string
+		values    []int
+		seek      func(c *intCursor) bool
+		forward   bool
+		wantValid bool
+		want      []int
+	}{
+		{"First", values, func(c *intCursor) bool { return c.First() }, true, true, values},
+		{"First Empty", []int{}, func(c *intCursor) bool { return c.First() }, true, false, []int{}},
+		{"Last", values, func(c *intCursor) bool { return c.Last() }, false, true, []int{67, 50, 44, 23, 14, 8, 5, 2, 1}},
+		{"Last Empty", []int{}, func(c *intCursor) bool { return c.Last() }, false, false, []int{}},
+		{"SeekGE Existing", values, func(c *intCursor) bool { return c.SeekGE(44) }, true, true, []int{44, 50, 67}},
+		{"SeekGE Missing", values, func(c *intCursor) bool { return c.SeekGE(45) }, true, true, []int{50, 67}},
+		{"SeekGE Above Max", values, func(c *intCursor) bool { return c.SeekGE(100) }, true, false, []int{}},
+		{"SeekLE Existing", values, func(c *intCursor) bool { return c.SeekLE(5) }, false, true, []int{5, 2, 1}},
+		{"SeekLE Missing", values, func(c *intCursor) bool { return c.SeekLE(7) }, false, true, []int{5, 2, 1}},
+		{"SeekLE Below Min", values, func(c *intCursor) bool { return c.SeekLE(0) }, false, false, []int{}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, tt.values)
+			c := t.Cursor()
+			if c.Valid() {
+				t1.Errorf("Valid() = true for a new cursor")
+			}
+			if valid := tt.seek(c); valid != tt.wantValid || c.Valid() != tt.wantValid {
+				t1.Errorf("seek = %v, Valid() = %v, want %v", valid, c.Valid(), tt.wantValid)
+			}
+
+			got := make([]int, 0, len(tt.want))
+			for ok := c.Valid(); ok; {
+				got = append(got, c.Key())
+				if tt.forward {
+					ok = c.Next()
+				} else {
+					ok = c.Prev()
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("got = %v, want %v", got, tt.want)
+			}
+			if c.Valid() || c.Next() || c.Prev() {
+				t1.Errorf("exhausted cursor is still valid")
+			}
+		})
+	}
+}
+
+func TestCursor_Lockstep(t1 *testing.T) {
+	a := newIntTree(t1, []int{1, 3, 5, 7, 9, 11})
+	b := newIntTree(t1, []int{2, 3, 4, 9, 10})
+
+	got := make([]int, 0)
+	ca, cb := a.Cursor(), b.Cursor()
+	ca.First()
+	cb.First()
+	for ca.Valid() && cb.Valid() {
+		switch {
+		case ca.Key() < cb.Key():
+			ca.Next()
+		case ca.Key() > cb.Key():
+			cb.Next()
+		default:
+			got = append(got, ca.Key())
+			ca.Next()
+			cb.Next()
+		}
+	}
+	if want := []int{3, 9}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("intersection = %v, want %v", got, want)
+	}
+}
+
+func TestCursor_Modification(t1 *testing.T) {
+	t := newIntTree(t1, []int{1, 2, 3, 4, 5, 6, 7, 8})
+	c := t.Cursor()
+	c.SeekGE(3)
+
+	// deleting the key under the cursor must not break iteration
+	t.Delete(3)
+	t.Delete(5)
+	if err := t.Insert(redblack.Ordered(10)); err != nil {
+		t1.Fatalf("Insert() error = %v", err)
+	}
+
+	got := make([]int, 0)
+	for c.Next() {
+		got = append(got, c.Key())
+	}
+	if want := []int{4, 6, 7, 8, 10}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("got = %v, want %v", got, want)
+	}
+}
+
+func TestCursor_Pull(t1 *testing.T) {
+	t := newIntTree(t1, []int{1, 2, 5, 8, 14})
+	c := t.Cursor()
+	c.SeekGE(2)
+	next, stop := c.Pull()
+
+	got := make([]int, 0)
+	for i := 0; i < 3; i++ {
+		v, ok := next()
+		if !ok {
+			t1.Fatalf("next() returned false after %d keys", i)
+		}
+		got = append(got, v)
+	}
+	if want := []int{2, 5, 8}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("next() = %v, want %v", got, want)
+	}
+
+	stop()
+	if v, ok := next(); ok || v != 0 {
+		t1.Errorf("next() after stop() = (%v, %v), want (0, false)", v, ok)
+	}
+	if c.Valid() {
+		t1.Errorf("Valid() = true after stop()")
+	}
+}
@@ -0,0 +1,82 @@
+package redblack
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DOTOptions configures the output of WriteDOT.
+type DOTOptions[V any] struct {
+	// Name is the name of the graph, "tree" if empty.
+	Name string
+	// NilLeaves adds the black NIL leaves of the red-black tree as extra nodes.
+	NilLeaves bool
+	// Label returns the label of the node with key k. If nil, the key is formatted with fmt.Sprint.
+	Label func(k V) string
+}
+
+// dotRecordEscaper escapes the characters that have a special meaning in the labels of record nodes.
+var dotRecordEscaper = strings.NewReplacer(
+	`\`, `\\`, `"`, `\"`, `|`, `\|`, `{`, `\{`, `}`, `\}`, `<`, `\<`, `>`, `\>`, "\n", `\n`,
+)
+
+// WriteDOT writes the structure of the tree as a Graphviz graph in the DOT language to w.
+// Nodes and the links to them are colored red or black. Each node has a left and a right port, from
+// which the edges to its children start. The output only depends on the structure of the tree, so that
+// the graphs of two trees can be compared line by line.
+func (t *Tree[V, T]) WriteDOT(w io.Writer, opts DOTOptions[V]) error {
+	name := opts.Name
+	if name == "" {
+		name = "tree"
+	}
+	label := opts.Label
+	if label == nil {
+		label = func(k V) string { return fmt.Sprint(k) }
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %q {\n", name)
+	fmt.Fprintln(bw, "\tnode [shape=record, style=filled, fontcolor=white];")
+
+	nodes, nils := 0, 0
+	// writeNode writes n and its subtree and returns the id of n
+	var writeNode func(n *Node[V, T]) string
+	writeNode = func(n *Node[V, T]) string {
+		if n == nil {
+			id := fmt.Sprintf("nil%d", nils)
+			nils++
+			fmt.Fprintf(bw, "\t%s [shape=box, label=\"NIL\", fillcolor=black, fontsize=8, width=0.3, height=0.2];\n", id)
+			return id
+		}
+		id := fmt.Sprintf("n%d", nodes)
+		nodes++
+		fmt.Fprintf(bw, "\t%s [label=\"<l>|%s|<r>\", fillcolor=%s];\n", id, dotRecordEscaper.Replace(label(n.Value())), dotColor(n.red))
+		for _, c := range []struct {
+			port  string
+			child *Node[V, T]
+		}{{"l", n.left}, {"r", n.right}} {
+			if c.child == nil && !opts.NilLeaves {
+				continue
+			}
+			childID := writeNode(c.child)
+			fmt.Fprintf(bw, "\t%s:%s -> %s [color=%s];\n", id, c.port, childID, dotColor(isRed(c.child)))
+		}
+		return id
+	}
+	if t.root != nil || opts.NilLeaves {
+		writeNode(t.root)
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotColor returns the DOT color of a node or link.
+func dotColor(red bool) string {
+	if red {
+		return "red"
+	}
+	return "black"
+}
@@ -0,0 +1,89 @@
+package redblack_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func TestTree_WriteDOT(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		opts   redblack.DOTOptions[int]
+		want   string
+	}{
+		{"Empty Tree", []int{}, redblack.DOTOptions[int]{}, `digraph "tree" {
+	node [shape=record, style=filled, fontcolor=white];
+}
+`},
+		{"Red Children", []int{2, 1, 3}, redblack.DOTOptions[int]{Name: "example"}, `digraph "example" {
+	node [shape=record, style=filled, fontcolor=white];
+	n0 [label="<l>|2|<r>", fillcolor=black];
+	n1 [label="<l>|1|<r>", fillcolor=red];
+	n0:l -> n1 [color=red];
+	n2 [label="<l>|3|<r>", fillcolor=red];
+	n0:r -> n2 [color=red];
+}
+`},
+		{"Nil Leaves", []int{1, 2}, redblack.DOTOptions[int]{NilLeaves: true}, `digraph "tree" {
+	node [shape=record, style=filled, fontcolor=white];
+	n0 [label="<l>|2|<r>", fillcolor=black];
+	n1 [label="<l>|1|<r>", fillcolor=red];
+	nil0 [shape=box, label="NIL", fillcolor=black, fontsize=8, width=0.3, height=0.2];
+	n1:l -> nil0 [color=black];
+	nil1 [shape=box, label="NIL", fillcolor=black, fontsize=8, width=0.3, height=0.2];
+	n1:r -> nil1 [color=black];
+	n0:l -> n1 [color=red];
+	nil2 [shape=box, label="NIL", fillcolor=black, fontsize=8, width=0.3, height=0.2];
+	n0:r -> nil2 [color=black];
+}
+`},
+		{"Escaped Label", []int{1}, redblack.DOTOptions[int]{Label: func(k int) string { return "<" + strconv.Itoa(k) + `|"x">` }}, `digraph "tree" {
+	node [shape=record, style=filled, fontcolor=white];
+	n0 [label="<l>|\<1\|\"x\"\>|<r>", fillcolor=black];
+}
+`},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			// insert in the given order, so that the shape of the tree is known
+			t := new(intTree)
+			for _, v := range tt.values {
+				if err := t.Insert(redblack.Ordered(v)); err != nil {
+					t1.Fatalf("Insert() error = %v", err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := t.WriteDOT(&buf, tt.opts); err != nil {
+				t1.Fatalf("WriteDOT() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t1.Errorf("WriteDOT() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_WriteDOTLarge(t1 *testing.T) {
+	t := newIntTree(t1, rand.Perm(1000))
+	var buf bytes.Buffer
+	if err := t.WriteDOT(&buf, redblack.DOTOptions[int]{NilLeaves: true}); err != nil {
+		t1.Fatalf("WriteDOT() error = %v", err)
+	}
+	out := buf.String()
+	// a tree with n nodes has n+1 NIL leaves and 2n edges
+	if got := strings.Count(out, "label=\"NIL\""); got != 1001 {
+		t1.Errorf("WriteDOT() has %d NIL leaves, want 1001", got)
+	}
+	if got := strings.Count(out, " -> "); got != 2000 {
+		t1.Errorf("WriteDOT() has %d edges, want 2000", got)
+	}
+	if got := strings.Count(out, "fillcolor=red"); got == 0 {
+		t1.Errorf("WriteDOT() has no red nodes")
+	}
+}
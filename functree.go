@@ -0,0 +1,187 @@
+package redblack
+
+import "iter"
+
+// funcItem stores a key together with the comparator that orders it.
+type funcItem[K any] struct {
+	key K
+	cmp func(a, b K) int
+}
+
+func (e funcItem[K]) CompareTo(other K) int {
+	return e.cmp(e.key, other)
+}
+
+func (e funcItem[K]) Value() K {
+	return e.key
+}
+
+// FuncTree is a red-black tree that stores plain keys and orders them with a comparator function.
+// This allows ordering types that do not implement Orderable, e.g. types of other packages.
+// Create trees with NewTreeFunc, the zero value has no comparator.
+type FuncTree[K any] struct {
+	tree Tree[K, funcItem[K]]
+	cmp  func(a, b K) int
+}
+
+// NewTreeFunc creates a new empty tree that orders its keys with cmp.
+// Like for slices.SortFunc, cmp(a, b) returns a negative number if a < b, a positive number if a > b
+// and 0 if a and b are equal.
+func NewTreeFunc[K any](cmp func(a, b K) int) *FuncTree[K] {
+	return &FuncTree[K]{cmp: cmp}
+}
+
+// Search returns true if the key is found in the tree and the key stored in the tree.
+// If the key is not found, the second return value is the key itself.
+func (t *FuncTree[K]) Search(k K) (bool, K) {
+	return t.tree.Search(k)
+}
+
+// SearchUpper returns the smallest key in the tree that is greater than or equal to the given key.
+// Returns KeyDoesNotExistError if k > i for all i in the tree.
+func (t *FuncTree[K]) SearchUpper(k K) (K, error) {
+	return t.tree.SearchUpper(k)
+}
+
+// SearchLower returns the largest key in the tree that is less than or equal to the given key.
+// Returns KeyDoesNotExistError if k < i for all i in the tree.
+func (t *FuncTree[K]) SearchLower(k K) (K, error) {
+	return t.tree.SearchLower(k)
+}
+
+// Contains returns true if the key is found in the tree.
+func (t *FuncTree[K]) Contains(k K) bool {
+	return t.tree.root.search(k) != nil
+}
+
+// Insert adds the key to the tree.
+// Returns KeyExistsError if the key already exists in the tree.
+func (t *FuncTree[K]) Insert(k K) error {
+	return t.tree.Insert(funcItem[K]{key: k, cmp: t.cmp})
+}
+
+// Delete removes the key from the tree.
+// Returns false if the key is not found.
+func (t *FuncTree[K]) Delete(k K) bool {
+	return t.tree.Delete(k)
+}
+
+// DeleteMin removes the smallest key from the tree.
+func (t *FuncTree[K]) DeleteMin() {
+	t.tree.DeleteMin()
+}
+
+// DeleteMax removes the largest key from the tree.
+func (t *FuncTree[K]) DeleteMax() {
+	t.tree.DeleteMax()
+}
+
+// PopMin removes the smallest key from the tree and returns it.
+// Returns false if the tree is empty.
+func (t *FuncTree[K]) PopMin() (K, bool) {
+	return t.tree.PopMin()
+}
+
+// PopMax removes the largest key from the tree and returns it.
+// Returns false if the tree is empty.
+func (t *FuncTree[K]) PopMax() (K, bool) {
+	return t.tree.PopMax()
+}
+
+// Height return the height of the tree.
+func (t *FuncTree[K]) Height() int {
+	return t.tree.Height()
+}
+
+// BlackHeight returns the number of black nodes on any path between the root and a leaf.
+func (t *FuncTree[K]) BlackHeight() int {
+	return t.tree.BlackHeight()
+}
+
+// MinDepth returns the number of nodes on the shortest path between the root and a leaf.
+func (t *FuncTree[K]) MinDepth() int {
+	return t.tree.MinDepth()
+}
+
+// Len returns the number of keys in the tree.
+func (t *FuncTree[K]) Len() int {
+	return t.tree.Len()
+}
+
+// Min returns the smallest key in the tree, the zero value for an empty tree.
+func (t *FuncTree[K]) Min() K {
+	return t.tree.Min()
+}
+
+// MinOk returns the smallest key in the tree.
+// Returns false if the tree is empty.
+func (t *FuncTree[K]) MinOk() (K, bool) {
+	return t.tree.MinOk()
+}
+
+// Max returns the largest key in the tree, the zero value for an empty tree.
+func (t *FuncTree[K]) Max() K {
+	return t.tree.Max()
+}
+
+// MaxOk returns the largest key in the tree.
+// Returns false if the tree is empty.
+func (t *FuncTree[K]) MaxOk() (K, bool) {
+	return t.tree.MaxOk()
+}
+
+// Select returns the i-th smallest key in the tree, counting from 0.
+// Returns IndexOutOfRangeError if i < 0 or i >= t.Len().
+func (t *FuncTree[K]) Select(i int) (K, error) {
+	return t.tree.Select(i)
+}
+
+// Rank returns the number of keys in the tree that are less than k.
+func (t *FuncTree[K]) Rank(k K) int {
+	return t.tree.Rank(k)
+}
+
+// CountRange returns the number of keys k in the tree with lo <= k < hi.
+func (t *FuncTree[K]) CountRange(lo, hi K) int {
+	return t.tree.CountRange(lo, hi)
+}
+
+// ToSortedSlice returns a sorted slice of the keys in the tree.
+func (t *FuncTree[K]) ToSortedSlice() []K {
+	return t.tree.ToSortedSlice()
+}
+
+// Sorted returns an iterator that yields the keys in the tree in sorted order.
+func (t *FuncTree[K]) Sorted() iter.Seq[K] {
+	return t.tree.Sorted()
+}
+
+// Backward returns an iterator that yields the keys in the tree in descending order.
+func (t *FuncTree[K]) Backward() iter.Seq[K] {
+	return t.tree.Backward()
+}
+
+// Range returns an iterator that yields the keys k with lo <= k < hi in sorted order.
+func (t *FuncTree[K]) Range(lo, hi K) iter.Seq[K] {
+	return t.tree.Range(lo, hi)
+}
+
+// RangeClosed returns an iterator that yields the keys k with lo <= k <= hi in sorted order.
+func (t *FuncTree[K]) RangeClosed(lo, hi K) iter.Seq[K] {
+	return t.tree.RangeClosed(lo, hi)
+}
+
+// RangeFrom returns an iterator that yields the keys k with lo <= k in sorted order.
+func (t *FuncTree[K]) RangeFrom(lo K) iter.Seq[K] {
+	return t.tree.RangeFrom(lo)
+}
+
+// RangeTo returns an iterator that yields the keys k with k < hi in sorted order.
+func (t *FuncTree[K]) RangeTo(hi K) iter.Seq[K] {
+	return t.tree.RangeTo(hi)
+}
+
+// String returns a string representation of the tree.
+func (t *FuncTree[K]) String() string {
+	return t.tree.String()
+}
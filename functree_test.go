@@ -0,0 +1,95 @@
+package redblack_test
+
+import (
+	"cmp"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+// point is a type without any methods, like a type of another package.
+type point struct {
+	x, y int
+}
+
+func comparePoints(a, b point) int {
+	if c := cmp.Compare(a.x, b.x); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.y, b.y)
+}
+
+func TestFuncTree_Insert(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		cmp     func(a, b string) int
+		keys    []string
+		want    []string
+		wantErr bool
+	}{
+		{"Empty Tree", strings.Compare, []string{}, []string{}, false},
+		{"Lexicographic", strings.Compare, []string{"b", "c", "a"}, []string{"a", "b", "c"}, false},
+		{"Reverse", func(a, b string) int { return strings.Compare(b, a) }, []string{"b", "c", "a"}, []string{"c", "b", "a"}, false},
+		{"By Length", func(a, b string) int { return cmp.Compare(len(a), len(b)) }, []string{"ccc", "a", "bb"}, []string{"a", "bb", "ccc"}, false},
+		{"Equal By Comparator", func(a, b string) int { return cmp.Compare(len(a), len(b)) }, []string{"a", "b"}, []string{"a"}, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := redblack.NewTreeFunc(tt.cmp)
+			var err error
+			for _, k := range tt.keys {
+				if e := t.Insert(k); e != nil {
+					err = e
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("Insert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := t.ToSortedSlice(); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("ToSortedSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuncTree_Points(t1 *testing.T) {
+	t := redblack.NewTreeFunc(comparePoints)
+	var points []point
+	for _, i := range rand.Perm(100) {
+		p := point{i % 10, i / 10}
+		points = append(points, p)
+		if err := t.Insert(p); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	slices.SortFunc(points, comparePoints)
+
+	if got := slices.Collect(t.Sorted()); !reflect.DeepEqual(got, points) {
+		t1.Errorf("Sorted() = %v, want %v", got, points)
+	}
+	if got := t.Min(); got != (point{0, 0}) {
+		t1.Errorf("Min() = %v, want %v", got, point{0, 0})
+	}
+	if got, err := t.SearchUpper(point{3, 10}); err != nil || got != (point{4, 0}) {
+		t1.Errorf("SearchUpper() = %v, %v, want %v", got, err, point{4, 0})
+	}
+	if got := t.CountRange(point{2, 5}, point{3, 5}); got != 10 {
+		t1.Errorf("CountRange() = %v, want 10", got)
+	}
+	if want := points[20:30]; !reflect.DeepEqual(slices.Collect(t.Range(point{2, 0}, point{3, 0})), want) {
+		t1.Errorf("Range() = %v, want %v", slices.Collect(t.Range(point{2, 0}, point{3, 0})), want)
+	}
+
+	for _, p := range points[:50] {
+		if !t.Delete(p) {
+			t1.Errorf("Delete(%v) = false, want true", p)
+		}
+	}
+	if t.Contains(points[0]) || !t.Contains(points[50]) || t.Len() != 50 {
+		t1.Errorf("tree = %v after Delete(), want %v", t.ToSortedSlice(), points[50:])
+	}
+}
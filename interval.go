@@ -0,0 +1,155 @@
+package redblack
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Interval is a half-open interval [Start, End) with an associated value.
+type Interval[P cmp.Ordered, V any] struct {
+	Start P
+	End   P
+	Value V
+}
+
+// intervalKey orders intervals by start, then by end. seq distinguishes intervals with equal bounds.
+type intervalKey[P cmp.Ordered] struct {
+	start P
+	end   P
+	seq   uint64
+}
+
+// intervalItem stores an interval together with the maximum end point in the subtree of its node.
+type intervalItem[P cmp.Ordered, V any] struct {
+	key    intervalKey[P]
+	value  V
+	maxEnd P
+}
+
+func (e intervalItem[P, V]) CompareTo(other intervalKey[P]) int {
+	if c := cmp.Compare(e.key.start, other.start); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(e.key.end, other.end); c != 0 {
+		return c
+	}
+	return cmp.Compare(e.key.seq, other.seq)
+}
+
+func (e intervalItem[P, V]) Value() intervalKey[P] {
+	return e.key
+}
+
+func (e intervalItem[P, V]) aggregate(left, right *Node[intervalKey[P], intervalItem[P, V]]) intervalItem[P, V] {
+	e.maxEnd = e.key.end
+	if left != nil {
+		e.maxEnd = max(e.maxEnd, left.value.maxEnd)
+	}
+	if right != nil {
+		e.maxEnd = max(e.maxEnd, right.value.maxEnd)
+	}
+	return e
+}
+
+func (e intervalItem[P, V]) interval() Interval[P, V] {
+	return Interval[P, V]{Start: e.key.start, End: e.key.end, Value: e.value}
+}
+
+// IntervalTree is a red-black tree of half-open intervals [start, end), ordered by their start points.
+// Every node keeps the maximum end point of its subtree, so that overlapping intervals can be found
+// without visiting subtrees that end too early. The tree may contain several intervals with equal bounds.
+// The zero value is an empty tree ready to use.
+type IntervalTree[P cmp.Ordered, V any] struct {
+	tree Tree[intervalKey[P], intervalItem[P, V]]
+	seq  uint64
+}
+
+// NewIntervalTree creates a new empty interval tree.
+func NewIntervalTree[P cmp.Ordered, V any]() *IntervalTree[P, V] {
+	return new(IntervalTree[P, V])
+}
+
+// Insert adds the interval [start, end) with the value v to the tree.
+// Returns EmptyIntervalError if end <= start.
+func (t *IntervalTree[P, V]) Insert(start, end P, v V) error {
+	if end <= start {
+		return EmptyIntervalError
+	}
+	t.seq++
+	return t.tree.Insert(intervalItem[P, V]{key: intervalKey[P]{start: start, end: end, seq: t.seq}, value: v})
+}
+
+// Delete removes an interval [start, end) from the tree. If there are several intervals with these bounds,
+// the one that has been inserted first is removed.
+// Returns false if there is no such interval.
+func (t *IntervalTree[P, V]) Delete(start, end P) bool {
+	n := t.tree.root.searchUpper(intervalKey[P]{start: start, end: end})
+	if n == nil || n.value.key.start != start || n.value.key.end != end {
+		return false
+	}
+	return t.tree.Delete(n.value.key)
+}
+
+// Len returns the number of intervals in the tree.
+func (t *IntervalTree[P, V]) Len() int {
+	return t.tree.Len()
+}
+
+// All returns an iterator that yields the intervals in the tree ordered by their start and end points.
+func (t *IntervalTree[P, V]) All() iter.Seq[Interval[P, V]] {
+	return func(yield func(Interval[P, V]) bool) {
+		t.tree.root.walkInOrder(func(n *Node[intervalKey[P], intervalItem[P, V]]) bool {
+			return n == nil || yield(n.value.interval())
+		})
+	}
+}
+
+// Stabbing returns an iterator that yields the intervals that contain x, i.e. start <= x < end,
+// ordered by their start points. Like Overlapping, iterating all k intervals takes O(k log n) in the worst case.
+func (t *IntervalTree[P, V]) Stabbing(x P) iter.Seq[Interval[P, V]] {
+	return func(yield func(Interval[P, V]) bool) {
+		t.overlapping(t.tree.root, x, x, true, yield)
+	}
+}
+
+// Overlapping returns an iterator that yields the intervals that overlap [a, b), i.e. start < b and a < end,
+// ordered by their start points. An empty range [a, b) with b <= a does not overlap any interval.
+//
+// Subtrees whose intervals all end at or before a are skipped, but the nodes on the paths down to the
+// overlapping intervals are visited even if they do not overlap [a, b) themselves. Iterating all k overlapping
+// intervals therefore takes O(log n) if k = 0 and O(k log n) in the worst case, not O(log n + k).
+func (t *IntervalTree[P, V]) Overlapping(a, b P) iter.Seq[Interval[P, V]] {
+	return func(yield func(Interval[P, V]) bool) {
+		if a < b {
+			t.overlapping(t.tree.root, a, b, false, yield)
+		}
+	}
+}
+
+// Overlaps returns true if any interval in the tree overlaps [a, b) in O(log n).
+func (t *IntervalTree[P, V]) Overlaps(a, b P) bool {
+	for range t.Overlapping(a, b) {
+		return true
+	}
+	return false
+}
+
+// overlapping calls yield for the intervals in the subtree of n that overlap [a, b), or that contain a
+// if closed is true. Subtrees without such intervals are skipped.
+func (t *IntervalTree[P, V]) overlapping(n *Node[intervalKey[P], intervalItem[P, V]], a, b P, closed bool, yield func(Interval[P, V]) bool) bool {
+	// no interval in the subtree ends after a
+	if n == nil || n.value.maxEnd <= a {
+		return true
+	}
+	if !t.overlapping(n.left, a, b, closed, yield) {
+		return false
+	}
+	// all intervals in the right subtree start after n
+	if n.value.key.start > b || (n.value.key.start == b && !closed) {
+		return true
+	}
+	if n.value.key.end > a && !yield(n.value.interval()) {
+		return false
+	}
+	return t.overlapping(n.right, a, b, closed, yield)
+}
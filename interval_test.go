@@ -0,0 +1,192 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+type span = redblack.Interval[int, string]
+
+func newIntervalTree(t1 *testing.T, spans []span) *redblack.IntervalTree[int, string] {
+	t1.Helper()
+	t := redblack.NewIntervalTree[int, string]()
+	for _, i := range rand.Perm(len(spans)) {
+		if err := t.Insert(spans[i].Start, spans[i].End, spans[i].Value); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	return t
+}
+
+func TestIntervalTree_Overlapping(t1 *testing.T) {
+	meetings := []span{
+		{Start: 9, End: 10, Value: "standup"},
+		{Start: 10, End: 12, Value: "review"},
+		{Start: 11, End: 12, Value: "lunch"},
+		{Start: 13, End: 17, Value: "workshop"},
+	}
+	tests := []struct {
+		name string
+		a, b int
+		want []string
+	}{
+		{"Before", 0, 9, nil},
+		{"Touching End", 12, 13, nil},
+		{"Touching Start", 8, 9, nil},
+		{"One", 9, 10, []string{"standup"}},
+		{"Inside", 14, 15, []string{"workshop"}},
+		{"Several", 9, 12, []string{"standup", "review", "lunch"}},
+		{"Empty Range", 11, 11, nil},
+		{"All", 0, 24, []string{"standup", "review", "lunch", "workshop"}},
+	}
+	t := newIntervalTree(t1, meetings)
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var got []string
+			for i := range t.Overlapping(tt.a, tt.b) {
+				got = append(got, i.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Overlapping() = %v, want %v", got, tt.want)
+			}
+			if t.Overlaps(tt.a, tt.b) != (len(tt.want) > 0) {
+				t1.Errorf("Overlaps() = %v, want %v", !(len(tt.want) > 0), len(tt.want) > 0)
+			}
+		})
+	}
+}
+
+func TestIntervalTree_Stabbing(t1 *testing.T) {
+	t := newIntervalTree(t1, []span{
+		{Start: 0, End: 5, Value: "a"},
+		{Start: 2, End: 3, Value: "b"},
+		{Start: 3, End: 8, Value: "c"},
+		{Start: 3, End: 8, Value: "d"},
+	})
+	tests := []struct {
+		x    int
+		want []string
+	}{
+		{-1, nil},
+		{0, []string{"a"}},
+		{2, []string{"a", "b"}},
+		{3, []string{"a", "c", "d"}},
+		{7, []string{"c", "d"}},
+		{8, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for i := range t.Stabbing(tt.x) {
+			got = append(got, i.Value)
+		}
+		// intervals with equal bounds are yielded in any order
+		slices.Sort(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t1.Errorf("Stabbing(%d) = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalTree_InsertDelete(t1 *testing.T) {
+	t := redblack.NewIntervalTree[int, string]()
+	if err := t.Insert(5, 5, ""); err != redblack.EmptyIntervalError {
+		t1.Errorf("Insert() error = %v, want %v", err, redblack.EmptyIntervalError)
+	}
+	if err := t.Insert(5, 4, ""); err != redblack.EmptyIntervalError {
+		t1.Errorf("Insert() error = %v, want %v", err, redblack.EmptyIntervalError)
+	}
+	for _, v := range []string{"first", "second"} {
+		if err := t.Insert(1, 3, v); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	if t.Delete(1, 2) || t.Delete(0, 3) {
+		t1.Errorf("Delete() = true for a missing interval")
+	}
+	if !t.Delete(1, 3) {
+		t1.Errorf("Delete() = false, want true")
+	}
+	if got := slices.Collect(t.All()); !reflect.DeepEqual(got, []span{{Start: 1, End: 3, Value: "second"}}) {
+		t1.Errorf("All() = %v after Delete(), want the second interval", got)
+	}
+	if !t.Delete(1, 3) || t.Len() != 0 {
+		t1.Errorf("Delete() did not remove the last interval")
+	}
+}
+
+func TestIntervalTree_Random(t1 *testing.T) {
+	t := redblack.NewIntervalTree[int, string]()
+	var spans []span
+	for i := 0; i < 2000; i++ {
+		if len(spans) > 0 && rand.Intn(3) == 0 {
+			j := rand.Intn(len(spans))
+			if !t.Delete(spans[j].Start, spans[j].End) {
+				t1.Fatalf("Delete(%d, %d) = false, want true", spans[j].Start, spans[j].End)
+			}
+			spans = slices.Delete(spans, j, j+1)
+		} else {
+			start := rand.Intn(1000)
+			end := start + 1 + rand.Intn(1<<rand.Intn(10))
+			if err := t.Insert(start, end, ""); err != nil {
+				t1.Fatalf("Insert() error = %v", err)
+			}
+			spans = append(spans, span{Start: start, End: end})
+		}
+
+		a, b := rand.Intn(1100)-50, rand.Intn(1100)-50
+		var want [][2]int
+		for _, s := range spans {
+			if a < b && s.Start < b && a < s.End {
+				want = append(want, [2]int{s.Start, s.End})
+			}
+		}
+		var got [][2]int
+		for s := range t.Overlapping(a, b) {
+			got = append(got, [2]int{s.Start, s.End})
+		}
+		sortPairs := func(p [][2]int) {
+			slices.SortFunc(p, func(x, y [2]int) int { return x[0]*2000 + x[1] - y[0]*2000 - y[1] })
+		}
+		sortPairs(want)
+		if !slices.IsSortedFunc(got, func(x, y [2]int) int { return x[0] - y[0] }) {
+			t1.Fatalf("Overlapping(%d, %d) = %v is not ordered by start", a, b, got)
+		}
+		sortPairs(got)
+		if !reflect.DeepEqual(got, want) {
+			t1.Fatalf("Overlapping(%d, %d) = %v, want %v", a, b, got, want)
+		}
+		stabbed := 0
+		for _, s := range spans {
+			if s.Start <= a && a < s.End {
+				stabbed++
+			}
+		}
+		if got := len(slices.Collect(t.Stabbing(a))); got != stabbed {
+			t1.Fatalf("Stabbing(%d) yields %d intervals, want %d", a, got, stabbed)
+		}
+	}
+	if t.Len() != len(spans) {
+		t1.Errorf("Len() = %v, want %v", t.Len(), len(spans))
+	}
+}
+
+func BenchmarkIntervalTree_Overlapping(b *testing.B) {
+	// every 64th interval reaches past the query point, all others end before it
+	t := redblack.NewIntervalTree[int, string]()
+	for i := 0; i < 1<<16; i++ {
+		end := i + 1
+		if i%64 == 0 {
+			end = 1 << 17
+		}
+		t.Insert(i, end, "")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range t.Overlapping(1<<16, 1<<16+1) {
+		}
+	}
+}
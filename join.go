@@ -0,0 +1,155 @@
+package redblack
+
+// blackHeight returns the number of black nodes on a path from n down to a leaf.
+func (n *Node[V, T]) blackHeight() int {
+	h := 0
+	for ; n != nil; n = n.left {
+		if !n.red {
+			h++
+		}
+	}
+	return h
+}
+
+// asRoot colors n black, so that it can be used as the root of a tree.
+// h is the black height of n; the black height after recoloring is returned.
+func asRoot[V any, T Orderable[V]](o *owner, n *Node[V, T], h int) (*Node[V, T], int) {
+	if !isRed(n) {
+		return n, h
+	}
+	n = n.mutable(o)
+	n.red = false
+	return n, h + 1
+}
+
+// join returns a tree with the keys of l, the item and the keys of r. All keys in l have to be less and all
+// keys in r greater than the key of item. l and r have to be black (or nil) with black heights lh and rh.
+// The root of the returned tree is black; its black height is returned as well.
+// join runs in O(|lh - rh| + 1).
+func join[V any, T Orderable[V]](o *owner, l *Node[V, T], lh int, item T, r *Node[V, T], rh int) (*Node[V, T], int) {
+	var n *Node[V, T]
+	switch {
+	case lh > rh:
+		n = joinRight(o, l, lh, item, r, rh)
+	case lh < rh:
+		n = joinLeft(o, l, lh, item, r, rh)
+	default:
+		n = &Node[V, T]{value: item, left: l, right: r, owner: o}
+		n.update(o)
+		return n, lh + 1
+	}
+	return asRoot(o, n, max(lh, rh))
+}
+
+// joinRight attaches item and r along the right spine of l, at the black node with black height rh.
+func joinRight[V any, T Orderable[V]](o *owner, l *Node[V, T], lh int, item T, r *Node[V, T], rh int) *Node[V, T] {
+	if !isRed(l) && lh == rh {
+		n := &Node[V, T]{value: item, red: true, left: l, right: r, owner: o}
+		n.update(o)
+		return n
+	}
+	l = l.mutable(o)
+	if !l.red {
+		lh--
+	}
+	l.right = joinRight(o, l.right, lh, item, r, rh)
+	return l.joinFixUp(o)
+}
+
+// joinLeft attaches l and item along the left spine of r, at the black node with black height lh.
+func joinLeft[V any, T Orderable[V]](o *owner, l *Node[V, T], lh int, item T, r *Node[V, T], rh int) *Node[V, T] {
+	if !isRed(r) && lh == rh {
+		n := &Node[V, T]{value: item, red: true, left: l, right: r, owner: o}
+		n.update(o)
+		return n
+	}
+	r = r.mutable(o)
+	if !r.red {
+		rh--
+	}
+	r.left = joinLeft(o, l, lh, item, r.left, rh)
+	return r.joinFixUp(o)
+}
+
+// joinFixUp restores the invariants after join attached a red node below n. In contrast to fixUp, it splits
+// a 4-node that received an additional red node by passing its middle node up to the parent.
+func (n *Node[V, T]) joinFixUp(o *owner) *Node[V, T] {
+	n.update(o)
+	if isRed(n.left) && isRed(n.right) && (isRed(n.left.left) || isRed(n.right.left)) {
+		n.flipColors(o)
+	}
+	if isRed(n.right) && !isRed(n.left) {
+		n = n.rotateLeft(o)
+	}
+	if isRed(n.left) && isRed(n.left.left) {
+		n = n.rotateRight(o)
+	}
+	return n
+}
+
+// split divides the tree rooted at the black node n with black height h into a tree with the keys less than k
+// and a tree with the keys greater than k. Both trees have black roots and are returned with their black
+// heights. If k is found, its node is returned as mid. split runs in O(log n).
+func split[V any, T Orderable[V]](o *owner, n *Node[V, T], h int, k V) (l *Node[V, T], lh int, mid *Node[V, T], r *Node[V, T], rh int) {
+	if n == nil {
+		return nil, 0, nil, nil, 0
+	}
+	left, leftH := asRoot(o, n.left, h-1)
+	right, rightH := asRoot(o, n.right, h-1)
+
+	if c := n.value.CompareTo(k); c == 0 {
+		return left, leftH, n, right, rightH
+	} else if c > 0 {
+		l, lh, mid, r, rh = split(o, left, leftH, k)
+		r, rh = join(o, r, rh, n.value, right, rightH)
+	} else {
+		l, lh, mid, r, rh = split(o, right, rightH, k)
+		l, lh = join(o, left, leftH, n.value, l, lh)
+	}
+	return l, lh, mid, r, rh
+}
+
+// Split divides the tree into a tree with the keys less than k and a tree with the keys greater than k
+// in O(log n). If k is in the tree, its item is returned as mid and found is true, so that Join(left, mid, right)
+// rebuilds the tree. The tree itself is not modified. It shares its nodes with the returned trees, which copy
+// them on write.
+func (t *Tree[V, T]) Split(k V) (left, right *Tree[V, T], mid T, found bool) {
+	l, _, m, r, _ := split(newOwner[V, T](), t.root, t.root.blackHeight(), k)
+	t.share()
+	if m != nil {
+		mid, found = m.value, true
+	}
+	return &Tree[V, T]{root: l, num: size(l)}, &Tree[V, T]{root: r, num: size(r)}, mid, found
+}
+
+// Join returns a tree with the keys of left, the pivot and the keys of right in O(log n).
+// All keys in left have to be less and all keys in right greater than the key of pivot, otherwise
+// NotSortedError or KeyExistsError is returned. A nil tree is treated as an empty tree.
+// left and right are not modified. They share their nodes with the returned tree, which copies them on write.
+func Join[V any, T Orderable[V]](left *Tree[V, T], pivot T, right *Tree[V, T]) (*Tree[V, T], error) {
+	if left == nil {
+		left = new(Tree[V, T])
+	}
+	if right == nil {
+		right = new(Tree[V, T])
+	}
+	if left.root != nil {
+		if c := left.root.max().value.CompareTo(pivot.Value()); c == 0 {
+			return nil, KeyExistsError
+		} else if c > 0 {
+			return nil, NotSortedError
+		}
+	}
+	if right.root != nil {
+		if c := right.root.min().value.CompareTo(pivot.Value()); c == 0 {
+			return nil, KeyExistsError
+		} else if c < 0 {
+			return nil, NotSortedError
+		}
+	}
+
+	root, _ := join(newOwner[V, T](), left.root, left.root.blackHeight(), pivot, right.root, right.root.blackHeight())
+	left.share()
+	right.share()
+	return &Tree[V, T]{root: root, num: size(root)}, nil
+}
@@ -0,0 +1,212 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+// checkInvariants reports an error for each red-black invariant that does not hold for t.
+func checkInvariants(t1 *testing.T, name string, t *redblack.Tree[int, redblack.Orderable[int]]) {
+	t1.Helper()
+	if !redblack.CheckNoRedRed(t) {
+		t1.Errorf("%s has red-red nodes", name)
+	}
+	if _, ok := redblack.CheckBlackHeight(t); !ok {
+		t1.Errorf("%s has different black-heights", name)
+	}
+	if !redblack.CheckLeftLeaning(t) {
+		t1.Errorf("%s is right-leaning", name)
+	}
+	if !redblack.CheckSize(t) {
+		t1.Errorf("%s has wrong subtree sizes", name)
+	}
+	if err := t.Validate(); err != nil {
+		t1.Errorf("%s is invalid: %v", name, err)
+	}
+}
+
+func TestTree_Split(t1 *testing.T) {
+	tests := []struct {
+		name      string
+		values    []int
+		k         int
+		wantLeft  []int
+		wantRight []int
+		wantFound bool
+	}{
+		{"Empty Tree", []int{}, 1, []int{}, []int{}, false},
+		{"One Element", []int{1}, 1, []int{}, []int{}, true},
+		{"Below Min", []int{1, 2, 3}, 0, []int{}, []int{1, 2, 3}, false},
+		{"Above Max", []int{1, 2, 3}, 4, []int{1, 2, 3}, []int{}, false},
+		{"Found", []int{1, 2, 3, 4, 5}, 3, []int{1, 2}, []int{4, 5}, true},
+		{"Not Found", []int{1, 3, 5, 7}, 4, []int{1, 3}, []int{5, 7}, false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, tt.values)
+			left, right, _, found := t.Split(tt.k)
+			if found != tt.wantFound {
+				t1.Errorf("Split() found = %v, want %v", found, tt.wantFound)
+			}
+			if got := left.ToSortedSlice(); !reflect.DeepEqual(got, tt.wantLeft) {
+				t1.Errorf("Split() left = %v, want %v", got, tt.wantLeft)
+			}
+			if got := right.ToSortedSlice(); !reflect.DeepEqual(got, tt.wantRight) {
+				t1.Errorf("Split() right = %v, want %v", got, tt.wantRight)
+			}
+			if got := t.ToSortedSlice(); !reflect.DeepEqual(got, tt.values) {
+				t1.Errorf("tree = %v after Split(), want %v", got, tt.values)
+			}
+		})
+	}
+}
+
+func TestTree_SplitRandom(t1 *testing.T) {
+	for n := 0; n < 200; n++ {
+		values := rand.Perm(2 * n)[:n]
+		t := newIntTree(t1, values)
+		slices.Sort(values)
+		k := rand.Intn(2*n + 1)
+		left, right, _, found := t.Split(k)
+
+		i, wantFound := slices.BinarySearch(values, k)
+		j := i
+		if wantFound {
+			j++
+		}
+		if found != wantFound {
+			t1.Errorf("n = %d: Split(%d) found = %v, want %v", n, k, found, wantFound)
+		}
+		if got := left.ToSortedSlice(); !slices.Equal(got, values[:i]) {
+			t1.Errorf("n = %d: Split(%d) left = %v, want %v", n, k, got, values[:i])
+		}
+		if got := right.ToSortedSlice(); !slices.Equal(got, values[j:]) {
+			t1.Errorf("n = %d: Split(%d) right = %v, want %v", n, k, got, values[j:])
+		}
+		if left.Len() != i || right.Len() != len(values)-j {
+			t1.Errorf("n = %d: Split(%d) Len() = (%d, %d), want (%d, %d)", n, k, left.Len(), right.Len(), i, len(values)-j)
+		}
+		checkInvariants(t1, "left", left)
+		checkInvariants(t1, "right", right)
+
+		// modifying the results must not affect the original tree
+		for _, v := range values[:i] {
+			left.Delete(v)
+		}
+		if err := right.Insert(redblack.Ordered(-1)); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+		if got := t.ToSortedSlice(); !slices.Equal(got, values) {
+			t1.Errorf("n = %d: tree = %v after modifying the results, want %v", n, got, values)
+		}
+		checkInvariants(t1, "tree", t)
+	}
+}
+
+func TestTree_SplitJoin(t1 *testing.T) {
+	t := new(redblack.Tree[int, payload])
+	for i := 0; i < 100; i++ {
+		if err := t.Insert(payload{i, strconv.Itoa(i)}); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	for _, k := range []int{0, 42, 99} {
+		left, right, mid, found := t.Split(k)
+		if !found || mid != (payload{k, strconv.Itoa(k)}) {
+			t1.Fatalf("Split(%d) mid, found = %v, %v, want %v, true", k, mid, found, payload{k, strconv.Itoa(k)})
+		}
+		got, err := redblack.Join(left, mid, right)
+		if err != nil {
+			t1.Fatalf("Join() error = %v", err)
+		}
+		if got.Len() != t.Len() {
+			t1.Errorf("Join(Split(%d)) Len() = %d, want %d", k, got.Len(), t.Len())
+		}
+		// the items, not just the keys, are the same as in the original tree
+		for i := 0; i < 100; i++ {
+			if item, inserted := got.GetOrInsert(payload{i, ""}); inserted || item != (payload{i, strconv.Itoa(i)}) {
+				t1.Errorf("Join(Split(%d)) stores %v for %d, want %v", k, item, i, payload{i, strconv.Itoa(i)})
+			}
+		}
+	}
+	if _, _, mid, found := t.Split(100); found || mid != (payload{}) {
+		t1.Errorf("Split(100) mid, found = %v, %v, want %v, false", mid, found, payload{})
+	}
+}
+
+func TestJoin(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		left    []int
+		pivot   int
+		right   []int
+		want    []int
+		wantErr error
+	}{
+		{"Empty Trees", []int{}, 1, []int{}, []int{1}, nil},
+		{"Empty Left", []int{}, 1, []int{2, 3}, []int{1, 2, 3}, nil},
+		{"Empty Right", []int{1, 2}, 3, []int{}, []int{1, 2, 3}, nil},
+		{"Both", []int{1, 2}, 3, []int{4, 5, 6, 7}, []int{1, 2, 3, 4, 5, 6, 7}, nil},
+		{"Pivot Too Small", []int{1, 2}, 0, []int{4}, nil, redblack.NotSortedError},
+		{"Pivot Too Large", []int{1}, 5, []int{3, 4}, nil, redblack.NotSortedError},
+		{"Pivot In Left", []int{1, 2}, 2, []int{3}, nil, redblack.KeyExistsError},
+		{"Pivot In Right", []int{1}, 2, []int{2, 3}, nil, redblack.KeyExistsError},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			left, right := newIntTree(t1, tt.left), newIntTree(t1, tt.right)
+			got, err := redblack.Join(left, redblack.Orderable[int](redblack.Ordered(tt.pivot)), right)
+			if err != tt.wantErr {
+				t1.Fatalf("Join() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if keys := got.ToSortedSlice(); !reflect.DeepEqual(keys, tt.want) {
+				t1.Errorf("Join() = %v, want %v", keys, tt.want)
+			}
+			checkInvariants(t1, "Join()", got)
+		})
+	}
+}
+
+func TestJoinRandom(t1 *testing.T) {
+	for i := 0; i < 300; i++ {
+		// trees of very different sizes lead to joins deep down the spine
+		n, m := rand.Intn(1<<rand.Intn(10)), rand.Intn(1<<rand.Intn(10))
+		rightValues := rand.Perm(m)
+		for j := range rightValues {
+			rightValues[j] += n + 1
+		}
+		left, right := newIntTree(t1, rand.Perm(n)), newIntTree(t1, rightValues)
+		got, err := redblack.Join(left, redblack.Orderable[int](redblack.Ordered(n)), right)
+		if err != nil {
+			t1.Fatalf("Join() error = %v", err)
+		}
+		want := make([]int, n+m+1)
+		for j := range want {
+			want[j] = j
+		}
+		if keys := got.ToSortedSlice(); !slices.Equal(keys, want) {
+			t1.Errorf("Join() = %v, want %v", keys, want)
+		}
+		if got.Len() != len(want) {
+			t1.Errorf("Join() Len() = %v, want %v", got.Len(), len(want))
+		}
+		checkInvariants(t1, "Join()", got)
+
+		// the joined tree does not affect its inputs
+		got.Delete(n)
+		got.DeleteMin()
+		if left.Len() != n || right.Len() != m {
+			t1.Errorf("inputs Len() = (%d, %d) after modifying Join(), want (%d, %d)", left.Len(), right.Len(), n, m)
+		}
+		checkInvariants(t1, "left", left)
+		checkInvariants(t1, "right", right)
+	}
+}
@@ -0,0 +1,92 @@
+package redblack
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MarshalJSON encodes the keys of the tree as a JSON array in ascending order.
+// It has a value receiver like String, so that trees held by value are encoded as well.
+func (t Tree[V, T]) MarshalJSON() ([]byte, error) {
+	keys := t.ToSortedSlice()
+	if keys == nil {
+		// encode an empty tree as [] instead of null
+		keys = []V{}
+	}
+	return json.Marshal(keys)
+}
+
+// UnmarshalJSON replaces the contents of the tree with the keys of a JSON array.
+// The items are created with Ordered, so the keys have to be of a predeclared ordered type like int or string,
+// and T has to be Orderable[V] or the type returned by Ordered. For all other item types, use UnmarshalJSONFunc.
+func (t *Tree[V, T]) UnmarshalJSON(data []byte) error {
+	return t.UnmarshalJSONFunc(data, newOrderedItem[V, T])
+}
+
+// UnmarshalJSONFunc replaces the contents of the tree with the keys of a JSON array.
+// The items are created from the keys with newItem. If the keys are sorted, the tree is built in O(n).
+// Returns KeyExistsError if the array contains duplicate keys. Like for json.Unmarshal, null leaves the tree unchanged.
+func (t *Tree[V, T]) UnmarshalJSONFunc(data []byte, newItem func(k V) (T, error)) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var keys []V
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	items := make([]T, 0, len(keys))
+	for _, k := range keys {
+		item, err := newItem(k)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+	tree, err := FromSeq(slices.Values(items), false)
+	if err != nil {
+		return err
+	}
+	*t = *tree
+	return nil
+}
+
+// newOrderedItem creates the item for k with Ordered.
+// Returns NoItemConstructorError if V is not a predeclared ordered type or T cannot hold the wrapper.
+func newOrderedItem[V any, T Orderable[V]](k V) (T, error) {
+	var o any
+	switch k := any(k).(type) {
+	case int:
+		o = Ordered(k)
+	case int8:
+		o = Ordered(k)
+	case int16:
+		o = Ordered(k)
+	case int32:
+		o = Ordered(k)
+	case int64:
+		o = Ordered(k)
+	case uint:
+		o = Ordered(k)
+	case uint8:
+		o = Ordered(k)
+	case uint16:
+		o = Ordered(k)
+	case uint32:
+		o = Ordered(k)
+	case uint64:
+		o = Ordered(k)
+	case uintptr:
+		o = Ordered(k)
+	case float32:
+		o = Ordered(k)
+	case float64:
+		o = Ordered(k)
+	case string:
+		o = Ordered(k)
+	}
+	item, ok := o.(T)
+	if !ok {
+		return item, NoItemConstructorError
+	}
+	return item, nil
+}
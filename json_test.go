@@ -0,0 +1,127 @@
+package redblack_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func TestTree_MarshalJSON(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   string
+	}{
+		{"Empty Tree", []int{}, "[]"},
+		{"One Element", []int{1}, "[1]"},
+		{"Sorted Output", []int{3, -1, 2, 0}, "[-1,0,2,3]"},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := json.Marshal(newIntTree(t1, tt.values))
+			if err != nil {
+				t1.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t1.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_UnmarshalJSON(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []int
+		wantErr bool
+	}{
+		{"Empty Array", "[]", []int{}, false},
+		{"Null", "null", []int{42}, false},
+		{"Sorted", "[1,2,3,4,5]", []int{1, 2, 3, 4, 5}, false},
+		{"Unsorted", "[4,1,5,3,2]", []int{1, 2, 3, 4, 5}, false},
+		{"Duplicates", "[1,2,2,3]", nil, true},
+		{"Wrong Type", `["a"]`, nil, true},
+		{"No Array", "{}", nil, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, []int{42})
+			err := json.Unmarshal([]byte(tt.data), t)
+			if (err != nil) != tt.wantErr {
+				t1.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := t.ToSortedSlice(); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+			checkInvariants(t1, "Unmarshal()", t)
+		})
+	}
+}
+
+func TestTree_JSONRoundTrip(t1 *testing.T) {
+	type document struct {
+		Tags *redblack.Tree[string, redblack.Orderable[string]] `json:"tags"`
+	}
+	tags := new(redblack.Tree[string, redblack.Orderable[string]])
+	for _, i := range rand.Perm(100) {
+		if err := tags.Insert(redblack.Ordered(strconv.Itoa(i))); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	data, err := json.Marshal(document{Tags: tags})
+	if err != nil {
+		t1.Fatalf("Marshal() error = %v", err)
+	}
+	var got document
+	if err := json.Unmarshal(data, &got); err != nil {
+		t1.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Tags.ToSortedSlice(), tags.ToSortedSlice()) {
+		t1.Errorf("Unmarshal() = %v, want %v", got.Tags.ToSortedSlice(), tags.ToSortedSlice())
+	}
+}
+
+func TestTree_JSONByValue(t1 *testing.T) {
+	type document struct {
+		Tags redblack.Tree[int, redblack.Orderable[int]] `json:"tags"`
+	}
+	doc := document{Tags: *newIntTree(t1, []int{3, 1, 2})}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t1.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"tags":[1,2,3]}` {
+		t1.Errorf("Marshal() = %s, want {\"tags\":[1,2,3]}", data)
+	}
+	var got document
+	if err := json.Unmarshal(data, &got); err != nil {
+		t1.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Tags.ToSortedSlice(), []int{1, 2, 3}) {
+		t1.Errorf("Unmarshal() = %v, want [1 2 3]", got.Tags.ToSortedSlice())
+	}
+}
+
+func TestTree_UnmarshalJSONFunc(t1 *testing.T) {
+	t := new(redblack.Tree[int, payload])
+	if err := json.Unmarshal([]byte("[1,2]"), t); err != redblack.NoItemConstructorError {
+		t1.Errorf("Unmarshal() error = %v, want %v", err, redblack.NoItemConstructorError)
+	}
+	err := t.UnmarshalJSONFunc([]byte("[3,1,2]"), func(k int) (payload, error) {
+		return payload{key: k, data: strconv.Itoa(k)}, nil
+	})
+	if err != nil {
+		t1.Fatalf("UnmarshalJSONFunc() error = %v", err)
+	}
+	if got := t.ToSortedSlice(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t1.Errorf("UnmarshalJSONFunc() = %v, want %v", got, []int{1, 2, 3})
+	}
+}
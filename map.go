@@ -0,0 +1,97 @@
+package redblack
+
+import (
+	"cmp"
+	"iter"
+)
+
+// mapEntry stores a key together with its value. Entries are ordered by their key only.
+type mapEntry[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+}
+
+func (e mapEntry[K, V]) CompareTo(other K) int {
+	return cmp.Compare(e.key, other)
+}
+
+func (e mapEntry[K, V]) Value() K {
+	return e.key
+}
+
+// Map is an ordered key/value map backed by a red-black tree.
+// The zero value is an empty map ready to use.
+type Map[K cmp.Ordered, V any] struct {
+	tree Tree[K, mapEntry[K, V]]
+}
+
+// NewMap creates a new empty map.
+func NewMap[K cmp.Ordered, V any]() *Map[K, V] {
+	return new(Map[K, V])
+}
+
+// Get returns the value stored for the key and true if the key is found.
+// If the key is not found, the zero value and false are returned.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	if n := m.tree.root.search(k); n != nil {
+		return n.value.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores the value for the key. An existing value for the key is replaced.
+func (m *Map[K, V]) Put(k K, v V) {
+	m.tree.ReplaceOrInsert(mapEntry[K, V]{key: k, value: v})
+}
+
+// Delete removes the key and its value from the map.
+// Returns false if the key is not found.
+func (m *Map[K, V]) Delete(k K) bool {
+	return m.tree.Delete(k)
+}
+
+// Contains returns true if the key is found in the map.
+func (m *Map[K, V]) Contains(k K) bool {
+	return m.tree.root.search(k) != nil
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return m.tree.Len()
+}
+
+// Keys returns an iterator that yields the keys of the map in ascending order.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator that yields the values of the map in ascending order of their keys.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator that yields the key/value pairs of the map in ascending order of the keys.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		f := func(n *Node[K, mapEntry[K, V]]) bool {
+			if n != nil {
+				return yield(n.value.key, n.value.value)
+			}
+			return true
+		}
+		m.tree.Walk(f, INORDER)
+	}
+}
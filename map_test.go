@@ -0,0 +1,111 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func TestMap_PutGet(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []int
+		get    int
+		want   string
+		wantOk bool
+	}{
+		{"Empty Map", []int{}, 1, "", false},
+		{"One Element", []int{1}, 1, "1", true},
+		{"Missing Key", []int{1, 2, 3}, 4, "", false},
+		{"Many Elements", rand.Perm(128), 42, "42", true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			m := redblack.NewMap[int, string]()
+			for _, k := range tt.keys {
+				m.Put(k, "")
+			}
+			// overwrite all values to check that Put replaces existing values
+			for _, k := range tt.keys {
+				m.Put(k, strconv.Itoa(k))
+			}
+
+			got, ok := m.Get(tt.get)
+			if ok != tt.wantOk || got != tt.want {
+				t1.Errorf("Get() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+			if m.Len() != len(tt.keys) {
+				t1.Errorf("Len() = %v, want %v", m.Len(), len(tt.keys))
+			}
+		})
+	}
+}
+
+func TestMap_Delete(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []int
+		delete  int
+		want    []int
+		wantErr bool
+	}{
+		{"Empty Map", []int{}, 1, []int{}, true},
+		{"One Element", []int{1}, 1, []int{}, false},
+		{"Five Elements", []int{1, 2, 3, 4, 5}, 3, []int{1, 2, 4, 5}, false},
+		{"Non-Existing Element", []int{1, 2, 3}, 4, []int{1, 2, 3}, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			m := redblack.NewMap[int, int]()
+			for _, k := range tt.keys {
+				m.Put(k, -k)
+			}
+			if success := m.Delete(tt.delete); success != !tt.wantErr {
+				t1.Errorf("Delete() = %v, want %v", success, !tt.wantErr)
+			}
+			if m.Contains(tt.delete) {
+				t1.Errorf("Contains() = true after Delete()")
+			}
+
+			keys := make([]int, 0, m.Len())
+			for k, v := range m.All() {
+				if v != -k {
+					t1.Errorf("All() yields value %v for key %v, want %v", v, k, -k)
+				}
+				keys = append(keys, k)
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t1.Errorf("All() = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_KeysValues(t1 *testing.T) {
+	m := new(redblack.Map[string, int])
+	for i, k := range []string{"d", "b", "a", "c", "e"} {
+		m.Put(k, i)
+	}
+
+	keys := make([]string, 0, m.Len())
+	for k := range m.Keys() {
+		keys = append(keys, k)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(keys, want) {
+		t1.Errorf("Keys() = %v, want %v", keys, want)
+	}
+
+	values := make([]int, 0, m.Len())
+	for v := range m.Values() {
+		values = append(values, v)
+		if len(values) == 3 {
+			break
+		}
+	}
+	if want := []int{2, 1, 3}; !reflect.DeepEqual(values, want) {
+		t1.Errorf("Values() = %v, want %v", values, want)
+	}
+}
@@ -0,0 +1,138 @@
+package redblack
+
+import "iter"
+
+// multiEntry stores an item together with the number of times its key has been inserted.
+// Entries are ordered by the key of the item only.
+type multiEntry[V any, T Orderable[V]] struct {
+	item  T
+	count int
+}
+
+func (e multiEntry[V, T]) CompareTo(other V) int {
+	return e.item.CompareTo(other)
+}
+
+func (e multiEntry[V, T]) Value() V {
+	return e.item.Value()
+}
+
+// MultiTree is a red-black tree that allows duplicate keys. Instead of storing equal keys in separate nodes,
+// it keeps a count for each distinct key. For each key, the item that has been inserted first is kept.
+// The zero value is an empty tree ready to use.
+type MultiTree[V any, T Orderable[V]] struct {
+	tree Tree[V, multiEntry[V, T]]
+	num  int
+}
+
+// Creates a new multi tree from a slice of Orderable items. Duplicate items are counted.
+func NewMultiTree[V any, T Orderable[V]](items []T) *MultiTree[V, T] {
+	m := new(MultiTree[V, T])
+	for _, item := range items {
+		m.Insert(item)
+	}
+	return m
+}
+
+// Insert adds the item to the tree once.
+func (m *MultiTree[V, T]) Insert(item T) {
+	m.InsertN(item, 1)
+}
+
+// InsertN adds the item to the tree n times. Does nothing if n <= 0.
+func (m *MultiTree[V, T]) InsertN(item T, n int) {
+	if n <= 0 {
+		return
+	}
+	m.num += n
+	if node := m.tree.root.search(item.Value()); node != nil {
+		node.value.count += n
+		return
+	}
+	// the key is not in the tree, so Insert cannot fail
+	_ = m.tree.Insert(multiEntry[V, T]{item: item, count: n})
+}
+
+// Count returns how many times the key k is stored in the tree.
+func (m *MultiTree[V, T]) Count(k V) int {
+	if n := m.tree.root.search(k); n != nil {
+		return n.value.count
+	}
+	return 0
+}
+
+// Contains returns true if the key k is stored in the tree at least once.
+func (m *MultiTree[V, T]) Contains(k V) bool {
+	return m.tree.root.search(k) != nil
+}
+
+// DeleteOne removes the key k from the tree once.
+// Returns false if the key is not found.
+func (m *MultiTree[V, T]) DeleteOne(k V) bool {
+	n := m.tree.root.search(k)
+	if n == nil {
+		return false
+	}
+	m.num--
+	if n.value.count > 1 {
+		n.value.count--
+		return true
+	}
+	return m.tree.Delete(k)
+}
+
+// DeleteAll removes all occurrences of the key k from the tree.
+// Returns the number of removed occurrences, which is 0 if the key is not found.
+func (m *MultiTree[V, T]) DeleteAll(k V) int {
+	n := m.tree.root.search(k)
+	if n == nil {
+		return 0
+	}
+	count := n.value.count
+	m.num -= count
+	m.tree.Delete(k)
+	return count
+}
+
+// Len returns the total number of stored keys, counting duplicates.
+func (m *MultiTree[V, T]) Len() int {
+	return m.num
+}
+
+// Distinct returns the number of distinct keys in the tree.
+func (m *MultiTree[V, T]) Distinct() int {
+	return m.tree.Len()
+}
+
+// Keys returns an iterator that yields each distinct key of the tree once in ascending order.
+func (m *MultiTree[V, T]) Keys() iter.Seq[V] {
+	return m.tree.Sorted()
+}
+
+// Sorted returns an iterator that yields the keys of the tree in ascending order.
+// A key that is stored multiple times is yielded as many times as it is counted.
+func (m *MultiTree[V, T]) Sorted() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for k, count := range m.All() {
+			for i := 0; i < count; i++ {
+				if !yield(k) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All returns an iterator that yields the distinct keys of the tree together with their counts
+// in ascending order of the keys.
+func (m *MultiTree[V, T]) All() iter.Seq2[V, int] {
+	return func(yield func(V, int) bool) {
+		f := func(n *Node[V, multiEntry[V, T]]) bool {
+			if n != nil {
+				return yield(n.value.Value(), n.value.count)
+			}
+			return true
+		}
+		m.tree.Walk(f, INORDER)
+	}
+}
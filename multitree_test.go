@@ -0,0 +1,132 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func newIntMultiTree(values []int) *redblack.MultiTree[int, redblack.Orderable[int]] {
+	items := make([]redblack.Orderable[int], 0, len(values))
+	for _, v := range values {
+		items = append(items, redblack.Ordered(v))
+	}
+	return redblack.NewMultiTree(items)
+}
+
+func TestMultiTree_Count(t1 *testing.T) {
+	tests := []struct {
+		name         string
+		values       []int
+		k            int
+		want         int
+		wantLen      int
+		wantDistinct int
+	}{
+		{"Empty Tree", []int{}, 1, 0, 0, 0},
+		{"One Element", []int{1}, 1, 1, 1, 1},
+		{"Missing Key", []int{1, 2, 2, 3}, 4, 0, 4, 3},
+		{"Duplicates", []int{3, 1, 3, 2, 3, 1}, 3, 3, 6, 3},
+		{"Only Duplicates", []int{5, 5, 5, 5}, 5, 4, 4, 1},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			m := newIntMultiTree(tt.values)
+			if got := m.Count(tt.k); got != tt.want {
+				t1.Errorf("Count() = %v, want %v", got, tt.want)
+			}
+			if got := m.Contains(tt.k); got != (tt.want > 0) {
+				t1.Errorf("Contains() = %v, want %v", got, tt.want > 0)
+			}
+			if got := m.Len(); got != tt.wantLen {
+				t1.Errorf("Len() = %v, want %v", got, tt.wantLen)
+			}
+			if got := m.Distinct(); got != tt.wantDistinct {
+				t1.Errorf("Distinct() = %v, want %v", got, tt.wantDistinct)
+			}
+			want := slices.Sorted(slices.Values(tt.values))
+			if got := slices.Collect(m.Sorted()); !slices.Equal(got, want) {
+				t1.Errorf("Sorted() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMultiTree_InsertNDelete(t1 *testing.T) {
+	m := new(redblack.MultiTree[int, redblack.Orderable[int]])
+	m.InsertN(redblack.Ordered(2), 3)
+	m.InsertN(redblack.Ordered(1), 0)
+	m.InsertN(redblack.Ordered(1), -2)
+	m.InsertN(redblack.Ordered(4), 2)
+	m.Insert(redblack.Ordered(2))
+
+	counts := map[int]int{}
+	for k, c := range m.All() {
+		counts[k] = c
+	}
+	if want := map[int]int{2: 4, 4: 2}; !reflect.DeepEqual(counts, want) {
+		t1.Errorf("All() = %v, want %v", counts, want)
+	}
+
+	if !m.DeleteOne(4) || m.Count(4) != 1 {
+		t1.Errorf("DeleteOne() left Count() = %v, want 1", m.Count(4))
+	}
+	if !m.DeleteOne(4) || m.Contains(4) {
+		t1.Errorf("DeleteOne() did not remove the last occurrence")
+	}
+	if m.DeleteOne(4) {
+		t1.Errorf("DeleteOne() = true for a missing key")
+	}
+	if got := m.DeleteAll(2); got != 4 {
+		t1.Errorf("DeleteAll() = %v, want 4", got)
+	}
+	if got := m.DeleteAll(2); got != 0 {
+		t1.Errorf("DeleteAll() = %v for a missing key, want 0", got)
+	}
+	if m.Len() != 0 || m.Distinct() != 0 {
+		t1.Errorf("Len(), Distinct() = %v, %v, want 0, 0", m.Len(), m.Distinct())
+	}
+}
+
+func TestMultiTree_RandomOperations(t1 *testing.T) {
+	m := new(redblack.MultiTree[int, redblack.Orderable[int]])
+	want := map[int]int{}
+	total := 0
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(50)
+		switch rand.Intn(4) {
+		case 0:
+			n := rand.Intn(4)
+			m.InsertN(redblack.Ordered(k), n)
+			want[k] += n
+			total += n
+		case 1:
+			m.Insert(redblack.Ordered(k))
+			want[k]++
+			total++
+		case 2:
+			if m.DeleteOne(k) != (want[k] > 0) {
+				t1.Fatalf("DeleteOne(%d) = %v, want %v", k, !(want[k] > 0), want[k] > 0)
+			}
+			if want[k] > 0 {
+				want[k]--
+				total--
+			}
+		case 3:
+			if got := m.DeleteAll(k); got != want[k] {
+				t1.Fatalf("DeleteAll(%d) = %v, want %v", k, got, want[k])
+			}
+			total -= want[k]
+			want[k] = 0
+		}
+		if got := m.Count(k); got != want[k] {
+			t1.Fatalf("Count(%d) = %v, want %v", k, got, want[k])
+		}
+		if m.Len() != total {
+			t1.Fatalf("Len() = %v, want %v", m.Len(), total)
+		}
+	}
+}
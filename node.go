@@ -1,41 +1,92 @@
 package redblack
 
-import (
-	"math"
-)
+import "sync/atomic"
 
 type Node[V any, T Orderable[V]] struct {
-	value       T
-	red         bool
-	left, right *Node[V, T]
+	value T
+	red   bool
+	// height is the number of nodes on the longest path from this node down to a leaf and minDepth the number
+	// of nodes on the shortest one. The height of a red-black tree is less than 128 for any number of nodes that
+	// fits into memory.
+	height, minDepth uint8
+	left, right      *Node[V, T]
+	// size is the number of nodes in the subtree rooted at this node.
+	size int
+	// owner is the tree that may modify this node in place.
+	owner *owner
+}
+
+// owner identifies the tree that may modify a node in place. Nodes with a different owner are
+// shared with other trees and have to be copied before they are modified.
+type owner struct {
+	// shared is set once the nodes of the owner are shared with another tree. It is atomic, because
+	// sharing only reads the tree and may happen concurrently.
+	shared atomic.Bool
+	// aggregate is a func(*Node[V, T]) that recomputes the subtree aggregate of a node, if the items of the
+	// tree implement aggregator, and nil otherwise.
+	aggregate any
+}
+
+// newOwner creates an owner token for nodes of type Node[V, T]. Whether the items keep subtree aggregates
+// is checked once here, so that updating the nodes of other trees does not pay for it.
+func newOwner[V any, T Orderable[V]]() *owner {
+	o := new(owner)
+	var item T
+	if _, ok := any(item).(aggregator[V, T]); ok {
+		o.aggregate = func(n *Node[V, T]) {
+			n.value = any(&n.value).(aggregator[V, T]).aggregate(n.left, n.right)
+		}
+	}
+	return o
+}
+
+// mutable returns n if it is owned by o, otherwise a copy of n that is owned by o.
+func (n *Node[V, T]) mutable(o *owner) *Node[V, T] {
+	if n == nil || n.owner == o {
+		return n
+	}
+	c := *n
+	c.owner = o
+	return &c
 }
 
 func (n *Node[V, T]) Value() V {
 	return n.value.Value()
 }
 
-func (n *Node[V, T]) height() chan int {
-	c := make(chan int)
-	go func(c chan int) {
-		if n == nil {
-			c <- 0
-			return
-		}
-		c1 := n.left.height()
-		c2 := n.right.height()
-		h1, h2 := <-c1, <-c2
-		if h1 > h2 {
-			c <- h1 + 1
-		} else {
-			c <- h2 + 1
-		}
-	}(c)
-	return c
+// height returns the height of the subtree rooted at n, 0 for nil.
+func height[V any, T Orderable[V]](n *Node[V, T]) int {
+	if n == nil {
+		return 0
+	}
+	return int(n.height)
+}
+
+// size returns the number of nodes in the subtree rooted at n, 0 for nil.
+func size[V any, T Orderable[V]](n *Node[V, T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
 }
 
-func (n *Node[V, T]) width() int {
-	h := <-n.height()
-	return int(math.Pow(2., float64(h-1)))
+// update recomputes the subtree metadata of n from its children.
+func (n *Node[V, T]) update(o *owner) {
+	n.size = size(n.left) + size(n.right) + 1
+	n.height = uint8(max(height(n.left), height(n.right)) + 1)
+	switch {
+	case n.left == nil && n.right == nil:
+		n.minDepth = 1
+	case n.left == nil:
+		n.minDepth = n.right.minDepth + 1
+	case n.right == nil:
+		n.minDepth = n.left.minDepth + 1
+	default:
+		n.minDepth = min(n.left.minDepth, n.right.minDepth) + 1
+	}
+	if o.aggregate != nil {
+		o.aggregate.(func(*Node[V, T]))(n)
+	}
 }
 
 func (n *Node[V, T]) min() *Node[V, T] {
@@ -64,6 +115,14 @@ func (n *Node[V, T]) walkInOrder(f func(*Node[V, T]) bool) bool {
 	}
 }
 
+func (n *Node[V, T]) walkReverseInOrder(f func(*Node[V, T]) bool) bool {
+	if n == nil {
+		return f(n)
+	} else {
+		return n.right.walkReverseInOrder(f) && f(n) && n.left.walkReverseInOrder(f)
+	}
+}
+
 func (n *Node[V, T]) walkPreOrder(f func(*Node[V, T]) bool) bool {
 	if n == nil {
 		return f(n)
@@ -91,6 +150,59 @@ func (n *Node[V, T]) walkLevelOrder(queue []*Node[V, T], f func(*Node[V, T]) boo
 	return true
 }
 
+// bound is one end of a key range. An unbounded end does not restrict the range.
+type bound[V any] struct {
+	key       V
+	inclusive bool
+	unbounded bool
+}
+
+// allows returns true if c, the result of comparing a key against the bound key, satisfies the bound.
+// For upper bounds, c has to be negated by the caller.
+func (b bound[V]) allows(c int) bool {
+	return c > 0 || (c == 0 && b.inclusive)
+}
+
+// walkRange visits the nodes with keys between lo and hi in ascending order. Subtrees outside the
+// bounds are skipped.
+func (n *Node[V, T]) walkRange(lo, hi bound[V], f func(*Node[V, T]) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := lo.unbounded || lo.allows(n.value.CompareTo(lo.key))
+	belowHi := hi.unbounded || hi.allows(-n.value.CompareTo(hi.key))
+	if aboveLo && !n.left.walkRange(lo, hi, f) {
+		return false
+	}
+	if aboveLo && belowHi && !f(n) {
+		return false
+	}
+	if belowHi {
+		return n.right.walkRange(lo, hi, f)
+	}
+	return true
+}
+
+// walkRangeBackward visits the nodes with keys between lo and hi in descending order. Subtrees
+// outside the bounds are skipped.
+func (n *Node[V, T]) walkRangeBackward(lo, hi bound[V], f func(*Node[V, T]) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := lo.unbounded || lo.allows(n.value.CompareTo(lo.key))
+	belowHi := hi.unbounded || hi.allows(-n.value.CompareTo(hi.key))
+	if belowHi && !n.right.walkRangeBackward(lo, hi, f) {
+		return false
+	}
+	if aboveLo && belowHi && !f(n) {
+		return false
+	}
+	if aboveLo {
+		return n.left.walkRangeBackward(lo, hi, f)
+	}
+	return true
+}
+
 func (n *Node[V, T]) search(k V) *Node[V, T] {
 	if n == nil {
 		return nil
@@ -140,6 +252,69 @@ func (n *Node[V, T]) searchLower(k V) *Node[V, T] {
 	return n.left.searchLower(k)
 }
 
+// searchNext returns the node with the smallest key in the tree that is strictly greater than k.
+func (n *Node[V, T]) searchNext(k V) *Node[V, T] {
+	if n == nil {
+		return nil
+	}
+
+	if n.value.CompareTo(k) <= 0 {
+		return n.right.searchNext(k)
+	}
+
+	nc := n.left.searchNext(k)
+	if nc == nil {
+		return n
+	}
+	return nc
+}
+
+// searchPrev returns the node with the largest key in the tree that is strictly less than k.
+func (n *Node[V, T]) searchPrev(k V) *Node[V, T] {
+	if n == nil {
+		return nil
+	}
+
+	if n.value.CompareTo(k) < 0 {
+		nc := n.right.searchPrev(k)
+		if nc == nil {
+			return n
+		}
+		return nc
+	}
+	return n.left.searchPrev(k)
+}
+
+// selectAt returns the node with the i-th smallest key (0-based) in the subtree rooted at n.
+func (n *Node[V, T]) selectAt(i int) *Node[V, T] {
+	for n != nil {
+		l := size(n.left)
+		if i < l {
+			n = n.left
+		} else if i > l {
+			i -= l + 1
+			n = n.right
+		} else {
+			return n
+		}
+	}
+	return nil
+}
+
+// rank returns the number of keys in the subtree rooted at n that are less than k.
+func (n *Node[V, T]) rank(k V) int {
+	r := 0
+	for n != nil {
+		if n.value.CompareTo(k) < 0 {
+			r += size(n.left) + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return r
+}
+
 type keyError string
 
 func (e keyError) Error() string {
@@ -149,138 +324,246 @@ func (e keyError) Error() string {
 const (
 	KeyExistsError       = keyError("Key already exists in tree.")
 	KeyDoesNotExistError = keyError("Key not found.")
+	IndexOutOfRangeError = keyError("Index out of range.")
+	NotSortedError       = keyError("Items are not sorted.")
+	EmptyIntervalError   = keyError("Interval end is not after its start.")
+	KeyChangedError      = keyError("Update must not change the key.")
 )
 
-func (n *Node[V, T]) insert(item T) (*Node[V, T], error) {
+// put inserts item into the subtree rooted at n. If the key of item exists already, the stored item is returned
+// as old and found is true. The stored item is replaced by item if replace is true, otherwise put returns nil
+// and leaves the subtree unchanged.
+func (n *Node[V, T]) put(o *owner, item T, replace bool) (_ *Node[V, T], old T, found bool) {
 	if n == nil {
-		return &Node[V, T]{value: item, red: true}, nil
+		n = &Node[V, T]{value: item, red: true, owner: o}
+		n.update(o)
+		return n, old, false
+	}
+
+	n = n.mutable(o)
+	flipped := isRed(n.left) && isRed(n.right)
+	if flipped {
+		n.flipColors(o)
 	}
 
-	if isRed(n.left) && isRed(n.right) {
-		n.flipColors()
+	var child *Node[V, T]
+	c := n.value.CompareTo(item.Value())
+	switch {
+	case c == 0:
+		old, found = n.value, true
+		if replace {
+			n.value = item
+		}
+	case c < 0:
+		if child, old, found = n.right.put(o, item, replace); child != nil {
+			n.right = child
+		}
+	default:
+		if child, old, found = n.left.put(o, item, replace); child != nil {
+			n.left = child
+		}
+	}
+	if found && !replace {
+		// undo the flip on the way back up, so that a failed insert leaves the tree unchanged
+		if flipped {
+			n.flipColors(o)
+		}
+		return nil, old, true
 	}
 
-	if c := n.value.CompareTo(item.Value()); c == 0 {
-		return nil, KeyExistsError
+	return n.fixUp(o), old, found
+}
+
+// modify replaces the item with the key k in the subtree rooted at n by f applied to it. Nodes are only copied
+// after k has been found, so the subtree is left unchanged if an error is returned.
+func (n *Node[V, T]) modify(o *owner, k V, f func(T) T) (*Node[V, T], error) {
+	if n == nil {
+		return nil, KeyDoesNotExistError
+	}
+
+	c := n.value.CompareTo(k)
+	if c == 0 {
+		item := f(n.value)
+		if item.CompareTo(k) != 0 {
+			return nil, KeyChangedError
+		}
+		n = n.mutable(o)
+		n.value = item
 	} else if c < 0 {
-		newNode, err := n.right.insert(item)
+		child, err := n.right.modify(o, k, f)
 		if err != nil {
 			return nil, err
 		}
-		n.right = newNode
+		n = n.mutable(o)
+		n.right = child
 	} else {
-		newNode, err := n.left.insert(item)
+		child, err := n.left.modify(o, k, f)
 		if err != nil {
 			return nil, err
 		}
-		n.left = newNode
+		n = n.mutable(o)
+		n.left = child
 	}
-
-	n = n.fixUp()
-
+	// the item may carry a subtree aggregate that depends on the replaced item
+	n.update(o)
 	return n, nil
 }
 
+// buildSorted builds a balanced subtree from sorted items without duplicates. The nodes at depth
+// redDepth are colored red, all other nodes are black.
+func buildSorted[V any, T Orderable[V]](o *owner, items []T, depth, redDepth int) *Node[V, T] {
+	if len(items) == 0 {
+		return nil
+	}
+	// the left subtree gets the larger half, so that single red children are always left children
+	m := len(items) / 2
+	n := &Node[V, T]{value: items[m], red: depth == redDepth, owner: o}
+	n.left = buildSorted(o, items[:m], depth+1, redDepth)
+	n.right = buildSorted(o, items[m+1:], depth+1, redDepth)
+	n.update(o)
+	return n
+}
+
 func isRed[V any, T Orderable[V]](n *Node[V, T]) bool {
 	return n != nil && n.red
 }
 
-func (n *Node[V, T]) flipColors() {
+// The following methods modify n in place and expect n to be owned by o.
+// Children are copied before they are modified if they are shared with another tree.
+
+func (n *Node[V, T]) flipColors(o *owner) {
+	n.left = n.left.mutable(o)
+	n.right = n.right.mutable(o)
 	n.red = !n.red
 	n.left.red = !n.left.red
 	n.right.red = !n.right.red
 }
 
-func (n *Node[V, T]) rotateLeft() *Node[V, T] {
-	x := n.right
+func (n *Node[V, T]) rotateLeft(o *owner) *Node[V, T] {
+	x := n.right.mutable(o)
 	n.right = x.left
 	x.left = n
 	x.red = n.red
 	n.red = true
+	n.update(o)
+	x.update(o)
 	return x
 }
 
-func (n *Node[V, T]) rotateRight() *Node[V, T] {
-	x := n.left
+func (n *Node[V, T]) rotateRight(o *owner) *Node[V, T] {
+	x := n.left.mutable(o)
 	n.left = x.right
 	x.right = n
 	x.red = n.red
 	n.red = true
+	n.update(o)
+	x.update(o)
 	return x
 }
 
-func (n *Node[V, T]) deleteMin() *Node[V, T] {
+// deleteMin removes the node with the smallest key from the subtree rooted at n and returns its item.
+func (n *Node[V, T]) deleteMin(o *owner) (*Node[V, T], T) {
 	if n.left == nil {
-		return nil
+		return nil, n.value
 	}
 
+	n = n.mutable(o)
 	if !isRed(n.left) && !isRed(n.left.left) {
-		n = n.moveRedLeft()
+		n = n.moveRedLeft(o)
+	}
+
+	var item T
+	n.left, item = n.left.deleteMin(o)
+
+	return n.fixUp(o), item
+}
+
+// deleteMax removes the node with the largest key from the subtree rooted at n and returns its item.
+func (n *Node[V, T]) deleteMax(o *owner) (*Node[V, T], T) {
+	n = n.mutable(o)
+	if isRed(n.left) && !isRed(n.right) {
+		n = n.rotateRight(o)
+	}
+	if n.right == nil {
+		return nil, n.value
+	}
+	if !isRed(n.right) && !isRed(n.right.left) {
+		n = n.moveRedRight(o)
 	}
 
-	n.left = n.left.deleteMin()
+	var item T
+	n.right, item = n.right.deleteMax(o)
 
-	return n.fixUp()
+	return n.fixUp(o), item
 }
 
-func (n *Node[V, T]) delete(k V) (*Node[V, T], bool) {
+// delete removes the node with the key k from the subtree rooted at n and returns its item.
+// If k is not found, the subtree may be restructured but still contains the same items.
+func (n *Node[V, T]) delete(o *owner, k V) (_ *Node[V, T], item T, found bool) {
 	if n == nil {
-		return nil, false
+		return nil, item, false
 	}
 
-	var success bool
+	n = n.mutable(o)
 	if n.value.CompareTo(k) > 0 {
+		if n.left == nil {
+			return n, item, false
+		}
 		if !isRed(n.left) && !isRed(n.left.left) {
-			n = n.moveRedLeft()
+			n = n.moveRedLeft(o)
 		}
-		n.left, success = n.left.delete(k)
+		n.left, item, found = n.left.delete(o, k)
 	} else {
-		if isRed(n.left) {
-			n = n.rotateRight()
+		if isRed(n.left) && !isRed(n.right) {
+			n = n.rotateRight(o)
 		}
 		if n.value.CompareTo(k) == 0 && n.right == nil {
-			return nil, true
+			return nil, n.value, true
 		}
 		if !isRed(n.right) && n.right != nil && !isRed(n.right.left) {
-			n = n.moveRedRight()
+			n = n.moveRedRight(o)
 		}
 		if n.value.CompareTo(k) == 0 {
-			n.value = n.right.min().value
-			n.right = n.right.deleteMin()
-			success = true
+			item, found = n.value, true
+			n.right, n.value = n.right.deleteMin(o)
 		} else {
-			n.right, success = n.right.delete(k)
+			n.right, item, found = n.right.delete(o, k)
 		}
 	}
 
-	return n.fixUp(), success
+	return n.fixUp(o), item, found
 }
 
-func (n *Node[V, T]) moveRedLeft() *Node[V, T] {
-	n.flipColors()
+func (n *Node[V, T]) moveRedLeft(o *owner) *Node[V, T] {
+	n.flipColors(o)
 	if isRed(n.right.left) {
-		n.right = n.right.rotateRight()
-		n = n.rotateLeft()
-		n.flipColors()
+		n.right = n.right.rotateRight(o)
+		n = n.rotateLeft(o)
+		n.flipColors(o)
+		// borrowing from a 4-node leaves its right red link behind
+		if isRed(n.right.right) {
+			n.right = n.right.rotateLeft(o)
+		}
 	}
 	return n
 }
 
-func (n *Node[V, T]) moveRedRight() *Node[V, T] {
-	n.flipColors()
+func (n *Node[V, T]) moveRedRight(o *owner) *Node[V, T] {
+	n.flipColors(o)
 	if isRed(n.left.left) {
-		n = n.rotateRight()
-		n.flipColors()
+		n = n.rotateRight(o)
+		n.flipColors(o)
 	}
 	return n
 }
 
-func (n *Node[V, T]) fixUp() *Node[V, T] {
+func (n *Node[V, T]) fixUp(o *owner) *Node[V, T] {
+	n.update(o)
 	if isRed(n.right) && !isRed(n.left) {
-		n = n.rotateLeft()
+		n = n.rotateLeft(o)
 	}
 	if isRed(n.left) && isRed(n.left.left) {
-		n = n.rotateRight()
+		n = n.rotateRight(o)
 	}
 	return n
 }
@@ -0,0 +1,68 @@
+package redblack
+
+import "iter"
+
+// PersistentTree is an immutable red-black tree. Insert and Delete leave the receiver unchanged and
+// return a new version of the tree instead. Only the nodes on the modified path are copied, all other
+// nodes are shared between the versions.
+//
+// Since a PersistentTree never changes, it can be read by multiple goroutines at the same time.
+// The zero value is an empty tree ready to use.
+type PersistentTree[V any, T Orderable[V]] struct {
+	root *Node[V, T]
+	num  int
+}
+
+// Snapshot returns the current state of the tree as a PersistentTree in O(1).
+// The tree stays usable; nodes that are shared with the snapshot are copied when the tree modifies them.
+func (t *Tree[V, T]) Snapshot() *PersistentTree[V, T] {
+	t.share()
+	return &PersistentTree[V, T]{root: t.root, num: t.num}
+}
+
+// Tree returns a mutable tree with the contents of p in O(1).
+// Modifications of the returned tree do not affect p.
+func (p *PersistentTree[V, T]) Tree() *Tree[V, T] {
+	return &Tree[V, T]{root: p.root, num: p.num}
+}
+
+// Insert returns a new version of the tree that additionally contains item.
+// Returns KeyExistsError if the key already exists in the tree.
+func (p *PersistentTree[V, T]) Insert(item T) (*PersistentTree[V, T], error) {
+	t := p.Tree()
+	if err := t.Insert(item); err != nil {
+		return p, err
+	}
+	return t.Snapshot(), nil
+}
+
+// Delete returns a new version of the tree without the key k.
+// Returns false and the receiver itself if the key is not found.
+func (p *PersistentTree[V, T]) Delete(k V) (*PersistentTree[V, T], bool) {
+	t := p.Tree()
+	if !t.Delete(k) {
+		return p, false
+	}
+	return t.Snapshot(), true
+}
+
+// Search returns true if the key is found in the tree and the value of the key.
+// If the key is not found, the second return value is the key itself.
+func (p *PersistentTree[V, T]) Search(k V) (bool, V) {
+	return p.Tree().Search(k)
+}
+
+// Len returns the number of nodes in the tree.
+func (p *PersistentTree[V, T]) Len() int {
+	return p.num
+}
+
+// Sorted returns an iterator that yields the keys in the tree in sorted order.
+func (p *PersistentTree[V, T]) Sorted() iter.Seq[V] {
+	return p.Tree().Sorted()
+}
+
+// ToSortedSlice returns a sorted slice of the keys in the tree.
+func (p *PersistentTree[V, T]) ToSortedSlice() []V {
+	return p.Tree().ToSortedSlice()
+}
@@ -0,0 +1,137 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func TestPersistentTree_Versions(t1 *testing.T) {
+	versions := []*redblack.PersistentTree[int, redblack.Orderable[int]]{{}}
+	want := [][]int{{}}
+	for i := 0; i < 500; i++ {
+		// modify a random older version, all versions must stay unchanged
+		j := rand.Intn(len(versions))
+		p, keys := versions[j], slices.Clone(want[j])
+		k := rand.Intn(100)
+		if rand.Intn(3) == 0 {
+			var success bool
+			p, success = p.Delete(k)
+			if idx, found := slices.BinarySearch(keys, k); found != success {
+				t1.Fatalf("Delete() = %v, want %v", success, found)
+			} else if found {
+				keys = slices.Delete(keys, idx, idx+1)
+			}
+		} else {
+			var err error
+			p, err = p.Insert(redblack.Ordered(k))
+			if idx, found := slices.BinarySearch(keys, k); found != (err == redblack.KeyExistsError) {
+				t1.Fatalf("Insert() error = %v, key exists %v", err, found)
+			} else if !found {
+				keys = slices.Insert(keys, idx, k)
+			}
+		}
+		versions = append(versions, p)
+		want = append(want, keys)
+	}
+
+	for i, p := range versions {
+		if got := p.ToSortedSlice(); !reflect.DeepEqual(got, want[i]) {
+			t1.Errorf("version %d = %v, want %v", i, got, want[i])
+		}
+		if p.Len() != len(want[i]) {
+			t1.Errorf("version %d Len() = %v, want %v", i, p.Len(), len(want[i]))
+		}
+		t := p.Tree()
+		if !redblack.CheckNoRedRed(t) {
+			t1.Errorf("version %d has red-red nodes", i)
+		}
+		if _, ok := redblack.CheckBlackHeight(t); !ok {
+			t1.Errorf("version %d has different black-heights", i)
+		}
+		if !redblack.CheckLeftLeaning(t) {
+			t1.Errorf("version %d is right-leaning", i)
+		}
+		if !redblack.CheckSize(t) {
+			t1.Errorf("version %d has wrong subtree sizes", i)
+		}
+	}
+}
+
+func TestTree_Snapshot(t1 *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		insert   []int
+		delete   []int
+		wantSnap []int
+		wantTree []int
+	}{
+		{"Empty Tree", []int{}, []int{1, 2}, []int{}, []int{}, []int{1, 2}},
+		{"Insert", []int{1, 2, 3}, []int{4, 5}, []int{}, []int{1, 2, 3}, []int{1, 2, 3, 4, 5}},
+		{"Delete", []int{1, 2, 3, 4, 5}, []int{}, []int{1, 3, 5}, []int{1, 2, 3, 4, 5}, []int{2, 4}},
+		{"Delete All", []int{1, 2, 3}, []int{}, []int{1, 2, 3}, []int{1, 2, 3}, []int{}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, tt.values)
+			snap := t.Snapshot()
+			for _, v := range tt.insert {
+				if err := t.Insert(redblack.Ordered(v)); err != nil {
+					t1.Fatalf("Insert() error = %v", err)
+				}
+			}
+			for _, v := range tt.delete {
+				t.Delete(v)
+			}
+
+			if got := snap.ToSortedSlice(); !reflect.DeepEqual(got, tt.wantSnap) {
+				t1.Errorf("snapshot = %v, want %v", got, tt.wantSnap)
+			}
+			if got := t.ToSortedSlice(); !reflect.DeepEqual(got, tt.wantTree) {
+				t1.Errorf("tree = %v, want %v", got, tt.wantTree)
+			}
+
+			// a tree created from the snapshot must not affect the snapshot either
+			other := snap.Tree()
+			for _, v := range tt.wantSnap {
+				other.Delete(v)
+			}
+			if got := snap.ToSortedSlice(); !reflect.DeepEqual(got, tt.wantSnap) {
+				t1.Errorf("snapshot = %v after modifying Tree(), want %v", got, tt.wantSnap)
+			}
+		})
+	}
+}
+
+func TestTree_SnapshotConcurrentReaders(t1 *testing.T) {
+	t := newIntTree(t1, rand.Perm(1000))
+	snap := t.Snapshot()
+	want := snap.ToSortedSlice()
+
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if got := snap.ToSortedSlice(); !reflect.DeepEqual(got, want) {
+					t1.Errorf("snapshot changed while the tree was modified")
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 2000; i++ {
+		if rand.Intn(2) == 0 {
+			t.Delete(rand.Intn(2000))
+		} else {
+			_ = t.Insert(redblack.Ordered(rand.Intn(2000)))
+		}
+	}
+	wg.Wait()
+}
@@ -1,15 +1,19 @@
 package redblack
 
 import (
-	"bytes"
+	"bufio"
 	"fmt"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 type RuneSet struct {
 	v, h               rune
 	ctl, ctr, cbl, cbr rune
 	bt, bb, bl, br     rune
+	// more marks nodes whose children are below the maximum depth
+	more rune
 }
 
 var (
@@ -17,151 +21,278 @@ var (
 		'│', '─',
 		'┌', '┐', '└', '┘',
 		'┴', '┬', '┤', '├',
+		'⋮',
 	}
 	boldRuneSet = RuneSet{
 		'║', '═',
 		'╔', '╗', '╚', '╝',
 		'╧', '╤', '╢', '╟',
+		'⋮',
 	}
+	asciiRuneSet = RuneSet{
+		'|', '-',
+		'+', '+', '+', '+',
+		'+', '+', '+', '+',
+		':',
+	}
+	asciiBoldRuneSet = RuneSet{
+		'#', '=',
+		'#', '#', '#', '#',
+		'#', '#', '#', '#',
+		':',
+	}
+)
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
 )
 
-func boxed(s string, bold, hasParent, hasLeft, hasRight bool) [3]string {
-	strLen := len(s)
-	if strLen%2 != 1 {
-		s = s + " "
-		strLen++
-	}
-
-	rs := &regularRuneSet
-	if bold {
-		rs = &boldRuneSet
-	}
-	ret := [3]string{}
-
-	// Build top string
-	topStringBuilder := strings.Builder{}
-	topStringBuilder.WriteRune(rs.ctl)
-	for i := 0; i < (strLen+2)/2; i++ {
-		topStringBuilder.WriteRune(rs.h)
-	}
-	if hasParent {
-		topStringBuilder.WriteRune(rs.bt)
-	} else {
-		topStringBuilder.WriteRune(rs.h)
-	}
-	for i := 0; i < (strLen+2)/2; i++ {
-		topStringBuilder.WriteRune(rs.h)
-	}
-	topStringBuilder.WriteRune(rs.ctr)
-	ret[0] = topStringBuilder.String()
-
-	// Build center string
-	cenStringBuilder := strings.Builder{}
-	cenStringBuilder.WriteRune(rs.v)
-	cenStringBuilder.WriteRune(' ')
-	cenStringBuilder.WriteString(s)
-	cenStringBuilder.WriteRune(' ')
-	cenStringBuilder.WriteRune(rs.v)
-	ret[1] = cenStringBuilder.String()
-
-	//	Build bottom string
-	botStringBuilder := strings.Builder{}
-	botStringBuilder.WriteRune(rs.cbl)
-	botStringBuilder.WriteString(bottomString(rs, strLen/2+1, hasLeft))
-	botStringBuilder.WriteRune(rs.h)
-	botStringBuilder.WriteString(bottomString(rs, strLen/2+1, hasRight))
-	botStringBuilder.WriteRune(rs.cbr)
-	ret[2] = botStringBuilder.String()
-
-	return ret
+// PrintOptions configures the output of Fprint.
+type PrintOptions[V any] struct {
+	// CellWidth is the number of runes reserved for the key of a node. Longer keys are cut.
+	// If 0, the width of the longest key is used.
+	CellWidth int
+	// ASCII draws the tree with ASCII characters instead of box-drawing characters.
+	ASCII bool
+	// Color prints the keys of red nodes in red using ANSI escape codes. Otherwise red nodes are drawn
+	// with bold boxes.
+	Color bool
+	// Format returns the text for the key k. If nil, the key is formatted with fmt.Sprint.
+	Format func(k V) string
+	// MaxDepth is the maximum number of printed levels. If 0, all levels are printed.
+	MaxDepth int
 }
 
-func bottomString(rs *RuneSet, len int, withSibling bool) string {
-	sb := strings.Builder{}
-	if withSibling {
-		p1 := len / 2
-		p2 := len - p1
-		for i := 0; i < p1; i++ {
-			sb.WriteRune(rs.h)
-		}
-		sb.WriteRune(rs.bb)
-		for i := 0; i < p2-1; i++ {
-			sb.WriteRune(rs.h)
+// placed is a node of the drawing at the column of its in-order position pos. left and right are the positions
+// of the children, or -1 if they are not drawn.
+type placed[V any, T Orderable[V]] struct {
+	n                *Node[V, T]
+	label            string
+	pos, left, right int
+}
+
+// canvas is a text area in which every line holds one rune per column. Escape codes for colored text are
+// kept apart from the runes as spans and only added when the canvas is written, so that they do not shift
+// the columns.
+type canvas struct {
+	lines [][]rune
+	// colored holds the colored spans [start, end) of every line, ordered by their columns
+	colored [][][2]int
+	// buf is reused for encoding the lines
+	buf []byte
+}
+
+func newCanvas(lines, width int) *canvas {
+	c := &canvas{lines: make([][]rune, lines), colored: make([][][2]int, lines)}
+	for i := range c.lines {
+		c.lines[i] = make([]rune, width)
+		for j := range c.lines[i] {
+			c.lines[i][j] = ' '
 		}
-	} else {
-		for i := 0; i < len; i++ {
-			sb.WriteRune(rs.h)
+	}
+	return c
+}
+
+// reset clears all lines, so that the canvas can be reused for the next level.
+func (c *canvas) reset() {
+	c.lines = c.lines[:cap(c.lines)]
+	for i := range c.lines {
+		for j := range c.lines[i] {
+			c.lines[i][j] = ' '
 		}
+		c.colored[i] = c.colored[i][:0]
 	}
-	return sb.String()
 }
 
-func buildConnector(len int, left bool) string {
-	sb := strings.Builder{}
-	if left {
-		sb.WriteRune(regularRuneSet.ctl)
-	} else {
-		sb.WriteRune(regularRuneSet.cbl)
+func (c *canvas) put(line, col int, r rune) {
+	c.lines[line][col] = r
+}
+
+func (c *canvas) hline(line, from, to int, r rune) {
+	for col := from; col < to; col++ {
+		c.put(line, col, r)
 	}
-	for i := 0; i < len-2; i++ {
-		sb.WriteRune(regularRuneSet.h)
+}
+
+// color marks the columns from, ..., to-1 of the line to be written in red.
+func (c *canvas) color(line, from, to int) {
+	c.colored[line] = append(c.colored[line], [2]int{from, to})
+}
+
+// blank returns true if the line contains only spaces.
+func (c *canvas) blank(line int) bool {
+	for _, r := range c.lines[line] {
+		if r != ' ' {
+			return false
+		}
 	}
-	if left {
-		sb.WriteRune(regularRuneSet.cbr)
-	} else {
-		sb.WriteRune(regularRuneSet.ctr)
+	return true
+}
+
+func (c *canvas) writeTo(w *bufio.Writer) {
+	for i, line := range c.lines {
+		end := len(line)
+		for end > 0 && line[end-1] == ' ' {
+			end--
+		}
+		buf, col := c.buf[:0], 0
+		for _, span := range c.colored[i] {
+			from, to := min(span[0], end), min(span[1], end)
+			if from >= to {
+				continue
+			}
+			buf = appendRunes(buf, line[col:from])
+			buf = append(buf, ansiRed...)
+			buf = appendRunes(buf, line[from:to])
+			buf = append(buf, ansiReset...)
+			col = to
+		}
+		buf = appendRunes(buf, line[col:end])
+		buf = append(buf, '\n')
+		w.Write(buf)
+		c.buf = buf
 	}
-	return sb.String()
 }
 
-// String returns a string representation of the tree.
-func (t Tree[V, T]) String() string {
-	level := t.GetTreeLevels()
-	h := len(level)
-	strLen := 9
-	w := (1 << (h - 1)) * (strLen + 3)
-	buffer := bytes.NewBuffer(make([]byte, 0, 1000))
-	for i, l := range level {
-		div := 1 << i
-		whitespace := (w/div - strLen) / 2
-		format := fmt.Sprintf("%%%ds%%s%%%ds", whitespace, whitespace)
-		stringBuilders := [4]strings.Builder{}
-		for _, n := range l {
-			if n != nil {
-				strings := boxed(fmt.Sprint(n.value), n.red, i > 0, n.left != nil, n.right != nil)
-				for k, s := range strings {
-					stringBuilders[k].WriteString(fmt.Sprintf(format, "", s, ""))
-					stringBuilders[k].WriteRune(' ')
-				}
+func appendRunes(buf []byte, runes []rune) []byte {
+	for _, r := range runes {
+		buf = utf8.AppendRune(buf, r)
+	}
+	return buf
+}
 
-				// print connectors
-				lenCon := whitespace/2 + strLen/3 - 1
-				formatCon := fmt.Sprintf("%%%ds%%%ds", (whitespace)/2+2, (whitespace)/2+2)
+// Fprint draws the tree with boxes for the nodes and writes it to w. Every node has its own columns, given by
+// its position in the sorted order, so the width of the drawing grows linearly with the number of nodes.
+func (t *Tree[V, T]) Fprint(w io.Writer, opts PrintOptions[V]) error {
+	format := opts.Format
+	if format == nil {
+		format = func(k V) string { return fmt.Sprint(k) }
+	}
 
-				if n.left != nil {
-					stringBuilders[3].WriteString(fmt.Sprintf(formatCon, "", buildConnector(lenCon, true)))
-				} else {
-					stringBuilders[3].WriteString(fmt.Sprintf(formatCon, "", ""))
-				}
-				for k := 0; k < strLen/3; k++ {
-					stringBuilders[3].WriteRune(' ')
+	// place the nodes level by level in the columns of their in-order positions, so that the width of the
+	// drawing grows with the number of nodes instead of doubling with every level
+	var levels [][]placed[V, T]
+	width, count := opts.CellWidth, 0
+	var place func(n *Node[V, T], depth int) int
+	place = func(n *Node[V, T], depth int) int {
+		p := placed[V, T]{n: n, left: -1, right: -1}
+		deeper := opts.MaxDepth <= 0 || depth+1 < opts.MaxDepth
+		if deeper && n.left != nil {
+			p.left = place(n.left, depth+1)
+		}
+		p.pos = count
+		count++
+		if deeper && n.right != nil {
+			p.right = place(n.right, depth+1)
+		}
+		p.label = format(n.Value())
+		if opts.CellWidth <= 0 {
+			width = max(width, utf8.RuneCountInString(p.label))
+		}
+		for len(levels) <= depth {
+			levels = append(levels, nil)
+		}
+		levels[depth] = append(levels[depth], p)
+		return p.pos
+	}
+
+	bw := bufio.NewWriter(w)
+	if t.root == nil {
+		return bw.Flush()
+	}
+	place(t.root, 0)
+
+	rs, bold, conn := &regularRuneSet, &boldRuneSet, &regularRuneSet
+	if opts.ASCII {
+		rs, bold, conn = &asciiRuneSet, &asciiBoldRuneSet, &asciiRuneSet
+	}
+	// the box has an odd width, so that the connectors are centered
+	boxWidth := width + 4
+	if boxWidth%2 == 0 {
+		boxWidth++
+	}
+	slot := boxWidth + 1
+	centerOf := func(pos int) int { return pos*slot + slot/2 }
+
+	c := newCanvas(4, count*slot)
+	for i, l := range levels {
+		c.reset()
+		for _, p := range l {
+			n := p.n
+			center := centerOf(p.pos)
+			left := center - boxWidth/2
+			right := left + boxWidth - 1
+			hasChildren := n.left != nil || n.right != nil
+
+			box := rs
+			if n.red && !opts.Color {
+				box = bold
+			}
+			// top and bottom border
+			c.put(0, left, box.ctl)
+			c.hline(0, left+1, right, box.h)
+			c.put(0, right, box.ctr)
+			c.put(2, left, box.cbl)
+			c.hline(2, left+1, right, box.h)
+			c.put(2, right, box.cbr)
+			if i > 0 {
+				c.put(0, center, box.bt)
+			}
+			if hasChildren {
+				c.put(2, center, box.bb)
+			}
+
+			// label, cut to the cell width
+			label := []rune(p.label)
+			if len(label) > width {
+				label = append(label[:width-1], box.more)
+			}
+			c.put(1, left, box.v)
+			c.put(1, right, box.v)
+			start := left + 2 + (right-left-3-len(label))/2
+			for k, r := range label {
+				c.put(1, start+k, r)
+			}
+			if n.red && opts.Color && len(label) > 0 {
+				c.color(1, start, start+len(label))
+			}
+
+			// connectors to the children on the next level
+			switch {
+			case !hasChildren:
+			case p.left < 0 && p.right < 0:
+				c.put(3, center, conn.more)
+			default:
+				if p.left >= 0 {
+					c.put(3, centerOf(p.left), conn.ctl)
+					c.hline(3, centerOf(p.left)+1, center, conn.h)
 				}
-				if n.right != nil {
-					stringBuilders[3].WriteString(fmt.Sprintf(formatCon, buildConnector(lenCon, false), ""))
-				} else {
-					stringBuilders[3].WriteString(fmt.Sprintf(formatCon, "", ""))
+				if p.right >= 0 {
+					c.hline(3, center+1, centerOf(p.right), conn.h)
+					c.put(3, centerOf(p.right), conn.ctr)
 				}
-				stringBuilders[3].WriteRune(' ')
-			} else {
-				for k := range stringBuilders {
-					stringBuilders[k].WriteString(fmt.Sprintf(format, "", "          ", ""))
+				switch {
+				case p.left >= 0 && p.right >= 0:
+					c.put(3, center, conn.bt)
+				case p.left >= 0:
+					c.put(3, center, conn.cbr)
+				default:
+					c.put(3, center, conn.cbl)
 				}
 			}
 		}
-		for _, sb := range stringBuilders {
-			buffer.WriteString(sb.String() + "\n")
-			sb.Reset()
+		if i == len(levels)-1 && c.blank(3) {
+			c.lines = c.lines[:3]
 		}
+		c.writeTo(bw)
 	}
-	return buffer.String()
+	return bw.Flush()
+}
+
+// String returns a string representation of the tree.
+func (t Tree[V, T]) String() string {
+	var sb strings.Builder
+	t.Fprint(&sb, PrintOptions[V]{})
+	return sb.String()
 }
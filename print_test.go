@@ -0,0 +1,134 @@
+package redblack_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func newStringTree(t1 *testing.T, values []string) *redblack.Tree[string, redblack.Orderable[string]] {
+	t1.Helper()
+	t := new(redblack.Tree[string, redblack.Orderable[string]])
+	for _, v := range values {
+		if err := t.Insert(redblack.Ordered(v)); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	return t
+}
+
+func TestTree_Fprint(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		opts   redblack.PrintOptions[string]
+		want   string
+	}{
+		{"Empty Tree", []string{}, redblack.PrintOptions[string]{}, ""},
+		{"ASCII", []string{"b", "a", "c", "d"}, redblack.PrintOptions[string]{ASCII: true}, `
+       +---+
+       | b |
+       +-+-+
+   +-----+-----------+
+ +-+-+             +-+-+
+ | a |             | d |
+ +---+             +-+-+
+               +-----+
+             #=#=#
+             # c #
+             #===#
+`},
+		{"Cell Width", []string{"bb", "aaaa"}, redblack.PrintOptions[string]{ASCII: true, CellWidth: 3}, `
+         +-----+
+         | bb  |
+         +--+--+
+    +-------+
+ #==#==#
+ # aa: #
+ #=====#
+`},
+		{"Max Depth", []string{"b", "a", "c", "d"}, redblack.PrintOptions[string]{ASCII: true, MaxDepth: 2}, `
+       +---+
+       | b |
+       +-+-+
+   +-----+-----+
+ +-+-+       +-+-+
+ | a |       | d |
+ +---+       +-+-+
+               :
+`},
+		{"Format", []string{"b", "a"}, redblack.PrintOptions[string]{ASCII: true, Format: strings.ToUpper}, `
+       +---+
+       | B |
+       +-+-+
+   +-----+
+ #=#=#
+ # A #
+ #===#
+`},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var sb strings.Builder
+			if err := newStringTree(t1, tt.values).Fprint(&sb, tt.opts); err != nil {
+				t1.Fatalf("Fprint() error = %v", err)
+			}
+			if got, want := sb.String(), strings.TrimPrefix(tt.want, "\n"); got != want {
+				t1.Errorf("Fprint() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestTree_FprintMultiByte(t1 *testing.T) {
+	t := newStringTree(t1, []string{"ß", "äöü", "a", "ñ"})
+	var sb strings.Builder
+	if err := t.Fprint(&sb, redblack.PrintOptions[string]{}); err != nil {
+		t1.Fatalf("Fprint() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	// the borders of a box end in the same column as the line with the key
+	for i := 0; i+2 < len(lines); i += 4 {
+		top, mid, bot := utf8.RuneCountInString(lines[i]), utf8.RuneCountInString(lines[i+1]), utf8.RuneCountInString(lines[i+2])
+		if top != mid || mid != bot {
+			t1.Errorf("box lines have different widths %d, %d, %d:\n%s", top, mid, bot, sb.String())
+		}
+	}
+}
+
+func TestTree_FprintColor(t1 *testing.T) {
+	t := newStringTree(t1, []string{"b", "a"})
+	var sb strings.Builder
+	if err := t.Fprint(&sb, redblack.PrintOptions[string]{ASCII: true, Color: true}); err != nil {
+		t1.Fatalf("Fprint() error = %v", err)
+	}
+	if want := "| \x1b[31ma\x1b[0m |"; !strings.Contains(sb.String(), want) {
+		t1.Errorf("Fprint() =\n%s\nwant red node %q", sb.String(), want)
+	}
+	if strings.Contains(sb.String(), "#") {
+		t1.Errorf("Fprint() =\n%s\nwant no bold boxes", sb.String())
+	}
+}
+
+func TestTree_FprintLarge(t1 *testing.T) {
+	// the width of the drawing grows with the number of nodes, so that large trees can be printed
+	items := make([]redblack.Orderable[int], 100000)
+	for i := range items {
+		items[i] = redblack.Ordered(i)
+	}
+	t, err := redblack.NewTreeFromSorted(items, false)
+	if err != nil {
+		t1.Fatalf("NewTreeFromSorted() error = %v", err)
+	}
+	start := time.Now()
+	if err := t.Fprint(io.Discard, redblack.PrintOptions[int]{}); err != nil {
+		t1.Fatalf("Fprint() error = %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t1.Errorf("Fprint() of %d nodes took %v", t.Len(), d)
+	}
+}
@@ -0,0 +1,183 @@
+package redblack
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// setItem stores a key of a Set.
+type setItem[K cmp.Ordered] struct {
+	key K
+}
+
+func (e setItem[K]) CompareTo(other K) int {
+	return cmp.Compare(e.key, other)
+}
+
+func (e setItem[K]) Value() K {
+	return e.key
+}
+
+// Set is an ordered set of keys backed by a red-black tree.
+// The zero value is an empty set ready to use.
+type Set[K cmp.Ordered] struct {
+	tree Tree[K, setItem[K]]
+}
+
+// NewSet creates a new set with the given keys. Duplicate keys are added once.
+func NewSet[K cmp.Ordered](keys ...K) *Set[K] {
+	// slices.Sorted orders like cmp.Compare, so that NaNs are adjacent and dropped as duplicates
+	keys = slices.Sorted(slices.Values(keys))
+	items := make([]setItem[K], len(keys))
+	for i, k := range keys {
+		items[i] = setItem[K]{key: k}
+	}
+	t, err := NewTreeFromSorted(items, true)
+	if err != nil {
+		// the keys are sorted, so this is a bug
+		panic(err)
+	}
+	return &Set[K]{tree: *t}
+}
+
+// Add adds the key to the set.
+// Returns false if the key is already in the set.
+func (s *Set[K]) Add(k K) bool {
+	_, inserted := s.tree.GetOrInsert(setItem[K]{key: k})
+	return inserted
+}
+
+// Remove removes the key from the set.
+// Returns false if the key is not in the set.
+func (s *Set[K]) Remove(k K) bool {
+	return s.tree.Delete(k)
+}
+
+// Contains returns true if the key is in the set.
+func (s *Set[K]) Contains(k K) bool {
+	return s.tree.root.search(k) != nil
+}
+
+// Len returns the number of keys in the set.
+func (s *Set[K]) Len() int {
+	return s.tree.Len()
+}
+
+// Clone returns a copy of the set in O(1). The sets share their nodes, which are copied on write.
+func (s *Set[K]) Clone() *Set[K] {
+	return &Set[K]{tree: *s.tree.Snapshot().Tree()}
+}
+
+// Min returns the smallest key in the set, the zero value for an empty set.
+func (s *Set[K]) Min() K {
+	return s.tree.Min()
+}
+
+// MinOk returns the smallest key in the set.
+// Returns false if the set is empty.
+func (s *Set[K]) MinOk() (K, bool) {
+	return s.tree.MinOk()
+}
+
+// Max returns the largest key in the set, the zero value for an empty set.
+func (s *Set[K]) Max() K {
+	return s.tree.Max()
+}
+
+// MaxOk returns the largest key in the set.
+// Returns false if the set is empty.
+func (s *Set[K]) MaxOk() (K, bool) {
+	return s.tree.MaxOk()
+}
+
+// PopMin removes the smallest key from the set and returns it.
+// Returns false if the set is empty.
+func (s *Set[K]) PopMin() (K, bool) {
+	return s.tree.PopMin()
+}
+
+// PopMax removes the largest key from the set and returns it.
+// Returns false if the set is empty.
+func (s *Set[K]) PopMax() (K, bool) {
+	return s.tree.PopMax()
+}
+
+// Ceiling returns the smallest key in the set that is greater than or equal to k.
+// Returns KeyDoesNotExistError if there is no such key.
+func (s *Set[K]) Ceiling(k K) (K, error) {
+	return s.tree.SearchUpper(k)
+}
+
+// Floor returns the largest key in the set that is less than or equal to k.
+// Returns KeyDoesNotExistError if there is no such key.
+func (s *Set[K]) Floor(k K) (K, error) {
+	return s.tree.SearchLower(k)
+}
+
+// Select returns the i-th smallest key in the set, counting from 0.
+// Returns IndexOutOfRangeError if i < 0 or i >= s.Len().
+func (s *Set[K]) Select(i int) (K, error) {
+	return s.tree.Select(i)
+}
+
+// Rank returns the number of keys in the set that are less than k.
+func (s *Set[K]) Rank(k K) int {
+	return s.tree.Rank(k)
+}
+
+// CountRange returns the number of keys k in the set with lo <= k < hi.
+func (s *Set[K]) CountRange(lo, hi K) int {
+	return s.tree.CountRange(lo, hi)
+}
+
+// All returns an iterator that yields the keys of the set in ascending order.
+func (s *Set[K]) All() iter.Seq[K] {
+	return s.tree.Sorted()
+}
+
+// Backward returns an iterator that yields the keys of the set in descending order.
+func (s *Set[K]) Backward() iter.Seq[K] {
+	return s.tree.Backward()
+}
+
+// Range returns an iterator that yields the keys k with lo <= k < hi in ascending order.
+func (s *Set[K]) Range(lo, hi K) iter.Seq[K] {
+	return s.tree.Range(lo, hi)
+}
+
+// RangeClosed returns an iterator that yields the keys k with lo <= k <= hi in ascending order.
+func (s *Set[K]) RangeClosed(lo, hi K) iter.Seq[K] {
+	return s.tree.RangeClosed(lo, hi)
+}
+
+// RangeFrom returns an iterator that yields the keys k with lo <= k in ascending order.
+func (s *Set[K]) RangeFrom(lo K) iter.Seq[K] {
+	return s.tree.RangeFrom(lo)
+}
+
+// RangeTo returns an iterator that yields the keys k with k < hi in ascending order.
+func (s *Set[K]) RangeTo(hi K) iter.Seq[K] {
+	return s.tree.RangeTo(hi)
+}
+
+// Union returns a new set with the keys that are in s or in other. Both sets are not modified.
+func (s *Set[K]) Union(other *Set[K]) *Set[K] {
+	return &Set[K]{tree: *s.tree.Union(&other.tree)}
+}
+
+// Intersection returns a new set with the keys that are in s and in other. Both sets are not modified.
+func (s *Set[K]) Intersection(other *Set[K]) *Set[K] {
+	return &Set[K]{tree: *s.tree.Intersection(&other.tree)}
+}
+
+// Difference returns a new set with the keys of s that are not in other. Both sets are not modified.
+func (s *Set[K]) Difference(other *Set[K]) *Set[K] {
+	return &Set[K]{tree: *s.tree.Difference(&other.tree)}
+}
+
+// SymmetricDifference returns a new set with the keys that are in exactly one of s and other.
+// Both sets are not modified.
+func (s *Set[K]) SymmetricDifference(other *Set[K]) *Set[K] {
+	return &Set[K]{tree: *s.tree.SymmetricDifference(&other.tree)}
+}
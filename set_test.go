@@ -0,0 +1,179 @@
+package redblack_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func TestSet_NewSet(t1 *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{"Empty Set", []string{}, []string{}},
+		{"One Key", []string{"a"}, []string{"a"}},
+		{"Unsorted Keys", []string{"c", "a", "b"}, []string{"a", "b", "c"}},
+		{"Duplicate Keys", []string{"b", "a", "b", "a"}, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			s := redblack.NewSet(tt.keys...)
+			if got := slices.AppendSeq([]string{}, s.All()); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("All() = %v, want %v", got, tt.want)
+			}
+			if s.Len() != len(tt.want) {
+				t1.Errorf("Len() = %v, want %v", s.Len(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSet_NewSetNaN(t1 *testing.T) {
+	s := redblack.NewSet(math.NaN(), 1, math.NaN(), 0)
+	if s.Len() != 3 {
+		t1.Errorf("Len() = %v, want 3", s.Len())
+	}
+	if !s.Contains(math.NaN()) {
+		t1.Errorf("Contains(NaN) = false, want true")
+	}
+	if got, _ := s.Select(1); got != 0 {
+		t1.Errorf("Select(1) = %v, want 0", got)
+	}
+}
+
+func TestSet_AddRemove(t1 *testing.T) {
+	var s redblack.Set[int]
+	want := make(map[int]bool)
+	for i := 0; i < 2000; i++ {
+		k := rand.Intn(200)
+		if rand.Intn(2) == 0 {
+			if got := s.Add(k); got == want[k] {
+				t1.Fatalf("Add(%v) = %v, want %v", k, got, !want[k])
+			}
+			want[k] = true
+		} else {
+			if got := s.Remove(k); got != want[k] {
+				t1.Fatalf("Remove(%v) = %v, want %v", k, got, want[k])
+			}
+			delete(want, k)
+		}
+		if s.Contains(k) != want[k] {
+			t1.Fatalf("Contains(%v) = %v, want %v", k, s.Contains(k), want[k])
+		}
+		if s.Len() != len(want) {
+			t1.Fatalf("Len() = %v, want %v", s.Len(), len(want))
+		}
+	}
+}
+
+func TestSet_Range(t1 *testing.T) {
+	s := redblack.NewSet(1, 3, 5, 7, 9)
+	tests := []struct {
+		name string
+		seq  func() []int
+		want []int
+	}{
+		{"All", func() []int { return slices.Collect(s.All()) }, []int{1, 3, 5, 7, 9}},
+		{"Backward", func() []int { return slices.Collect(s.Backward()) }, []int{9, 7, 5, 3, 1}},
+		{"Range", func() []int { return slices.Collect(s.Range(3, 7)) }, []int{3, 5}},
+		{"RangeClosed", func() []int { return slices.Collect(s.RangeClosed(3, 7)) }, []int{3, 5, 7}},
+		{"RangeFrom", func() []int { return slices.Collect(s.RangeFrom(4)) }, []int{5, 7, 9}},
+		{"RangeTo", func() []int { return slices.Collect(s.RangeTo(4)) }, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := tt.seq(); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("%s() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got, err := s.Ceiling(4); got != 5 || err != nil {
+		t1.Errorf("Ceiling() = %v, %v, want 5, nil", got, err)
+	}
+	if got, err := s.Floor(4); got != 3 || err != nil {
+		t1.Errorf("Floor() = %v, %v, want 3, nil", got, err)
+	}
+	if got := s.CountRange(2, 8); got != 3 {
+		t1.Errorf("CountRange() = %v, want 3", got)
+	}
+	if got := s.Rank(7); got != 3 {
+		t1.Errorf("Rank() = %v, want 3", got)
+	}
+}
+
+func TestSet_MinMax(t1 *testing.T) {
+	var s redblack.Set[float64]
+	if _, ok := s.MinOk(); ok {
+		t1.Errorf("MinOk() on empty set returned true")
+	}
+	if _, ok := s.PopMax(); ok {
+		t1.Errorf("PopMax() on empty set returned true")
+	}
+	s.Add(2.5)
+	s.Add(-1)
+	s.Add(0.5)
+	if got, ok := s.MinOk(); got != -1 || !ok {
+		t1.Errorf("MinOk() = %v, %v, want -1, true", got, ok)
+	}
+	if got, ok := s.MaxOk(); got != 2.5 || !ok {
+		t1.Errorf("MaxOk() = %v, %v, want 2.5, true", got, ok)
+	}
+	if got, ok := s.PopMin(); got != -1 || !ok {
+		t1.Errorf("PopMin() = %v, %v, want -1, true", got, ok)
+	}
+	if got := s.Min(); got != 0.5 {
+		t1.Errorf("Min() = %v, want 0.5", got)
+	}
+}
+
+func TestSet_SetOps(t1 *testing.T) {
+	a := redblack.NewSet(1, 2, 3, 4)
+	b := redblack.NewSet(3, 4, 5)
+	tests := []struct {
+		name string
+		got  *redblack.Set[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := slices.Collect(tt.got.All()); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("%s() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+	// the results share nodes with the inputs, which must stay unchanged
+	tests[0].got.Remove(3)
+	if got := slices.Collect(a.All()); !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t1.Errorf("a = %v after modifying the union, want [1 2 3 4]", got)
+	}
+}
+
+func TestSet_Clone(t1 *testing.T) {
+	s := redblack.NewSet(rand.Perm(100)...)
+	c := s.Clone()
+	for k := 0; k < 100; k += 2 {
+		c.Remove(k)
+	}
+	s.Add(100)
+	if s.Len() != 101 {
+		t1.Errorf("Len() = %v, want 101", s.Len())
+	}
+	if c.Len() != 50 {
+		t1.Errorf("Clone().Len() = %v, want 50", c.Len())
+	}
+	if c.Contains(100) || !s.Contains(0) {
+		t1.Errorf("modifications of the clone and the set affect each other")
+	}
+}
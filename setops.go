@@ -0,0 +1,150 @@
+package redblack
+
+// join2 returns a tree with the keys of l and the keys of r, which all have to be greater than the keys in l.
+// l and r have to be black (or nil) with black heights lh and rh.
+func join2[V any, T Orderable[V]](o *owner, l *Node[V, T], lh int, r *Node[V, T], rh int) (*Node[V, T], int) {
+	if l == nil {
+		return r, rh
+	}
+	if r == nil {
+		return l, lh
+	}
+	r, item := r.deleteMin(o)
+	r, rh = asRoot(o, r, r.blackHeight())
+	return join(o, l, lh, item, r, rh)
+}
+
+// children returns the children of the black node n with black height h as black roots.
+func (n *Node[V, T]) children(o *owner, h int) (left *Node[V, T], lh int, right *Node[V, T], rh int) {
+	left, lh = asRoot(o, n.left, h-1)
+	right, rh = asRoot(o, n.right, h-1)
+	return left, lh, right, rh
+}
+
+// union returns a tree with the keys of a and b. The items of a are kept for keys that are in both trees.
+func union[V any, T Orderable[V]](o *owner, a *Node[V, T], ah int, b *Node[V, T], bh int) (*Node[V, T], int) {
+	if a == nil {
+		return b, bh
+	}
+	if b == nil {
+		return a, ah
+	}
+	al, alh, ar, arh := a.children(o, ah)
+	bl, blh, _, br, brh := split(o, b, bh, a.value.Value())
+	l, lh := union(o, al, alh, bl, blh)
+	r, rh := union(o, ar, arh, br, brh)
+	return join(o, l, lh, a.value, r, rh)
+}
+
+// intersection returns a tree with the keys that are in a and in b. The items of a are kept.
+func intersection[V any, T Orderable[V]](o *owner, a *Node[V, T], ah int, b *Node[V, T], bh int) (*Node[V, T], int) {
+	if a == nil || b == nil {
+		return nil, 0
+	}
+	al, alh, ar, arh := a.children(o, ah)
+	bl, blh, mid, br, brh := split(o, b, bh, a.value.Value())
+	l, lh := intersection(o, al, alh, bl, blh)
+	r, rh := intersection(o, ar, arh, br, brh)
+	if mid != nil {
+		return join(o, l, lh, a.value, r, rh)
+	}
+	return join2(o, l, lh, r, rh)
+}
+
+// difference returns a tree with the keys of a that are not in b.
+func difference[V any, T Orderable[V]](o *owner, a *Node[V, T], ah int, b *Node[V, T], bh int) (*Node[V, T], int) {
+	if a == nil || b == nil {
+		return a, ah
+	}
+	bl, blh, br, brh := b.children(o, bh)
+	al, alh, _, ar, arh := split(o, a, ah, b.value.Value())
+	l, lh := difference(o, al, alh, bl, blh)
+	r, rh := difference(o, ar, arh, br, brh)
+	return join2(o, l, lh, r, rh)
+}
+
+// symmetricDifference returns a tree with the keys that are in exactly one of a and b.
+func symmetricDifference[V any, T Orderable[V]](o *owner, a *Node[V, T], ah int, b *Node[V, T], bh int) (*Node[V, T], int) {
+	if a == nil {
+		return b, bh
+	}
+	if b == nil {
+		return a, ah
+	}
+	al, alh, ar, arh := a.children(o, ah)
+	bl, blh, mid, br, brh := split(o, b, bh, a.value.Value())
+	l, lh := symmetricDifference(o, al, alh, bl, blh)
+	r, rh := symmetricDifference(o, ar, arh, br, brh)
+	if mid != nil {
+		return join2(o, l, lh, r, rh)
+	}
+	return join(o, l, lh, a.value, r, rh)
+}
+
+// setOp is the signature shared by union, intersection, difference and symmetricDifference.
+type setOp[V any, T Orderable[V]] func(o *owner, a *Node[V, T], ah int, b *Node[V, T], bh int) (*Node[V, T], int)
+
+// combine returns a new tree with the result of op applied to t and other. Both trees are not modified.
+func (t *Tree[V, T]) combine(other *Tree[V, T], op setOp[V, T]) *Tree[V, T] {
+	root, _ := op(newOwner[V, T](), t.root, t.root.blackHeight(), other.root, other.root.blackHeight())
+	t.share()
+	other.share()
+	return &Tree[V, T]{root: root, num: size(root)}
+}
+
+// combineWith replaces the contents of t with the result of op applied to t and other. other is not modified.
+func (t *Tree[V, T]) combineWith(other *Tree[V, T], op setOp[V, T]) {
+	// other shares its nodes with t afterwards. This has to happen before t takes the ownership,
+	// in case other is t itself.
+	other.share()
+	t.root, _ = op(t.mutation(), t.root, t.root.blackHeight(), other.root, other.root.blackHeight())
+	t.num = size(t.root)
+}
+
+// Union returns a new tree with the keys that are in t or in other in O(m log(n/m + 1)), where m is the
+// size of the smaller and n the size of the larger tree. For keys in both trees, the items of t are used.
+// Both trees are not modified.
+func (t *Tree[V, T]) Union(other *Tree[V, T]) *Tree[V, T] {
+	return t.combine(other, union[V, T])
+}
+
+// UnionWith adds the keys of other to t in O(m log(n/m + 1)). For keys in both trees, the items of t are kept.
+// other is not modified.
+func (t *Tree[V, T]) UnionWith(other *Tree[V, T]) {
+	t.combineWith(other, union[V, T])
+}
+
+// Intersection returns a new tree with the keys that are in t and in other in O(m log(n/m + 1)).
+// The items of t are used. Both trees are not modified.
+func (t *Tree[V, T]) Intersection(other *Tree[V, T]) *Tree[V, T] {
+	return t.combine(other, intersection[V, T])
+}
+
+// IntersectionWith removes the keys from t that are not in other in O(m log(n/m + 1)).
+// other is not modified.
+func (t *Tree[V, T]) IntersectionWith(other *Tree[V, T]) {
+	t.combineWith(other, intersection[V, T])
+}
+
+// Difference returns a new tree with the keys of t that are not in other in O(m log(n/m + 1)).
+// Both trees are not modified.
+func (t *Tree[V, T]) Difference(other *Tree[V, T]) *Tree[V, T] {
+	return t.combine(other, difference[V, T])
+}
+
+// DifferenceWith removes the keys of other from t in O(m log(n/m + 1)). other is not modified.
+func (t *Tree[V, T]) DifferenceWith(other *Tree[V, T]) {
+	t.combineWith(other, difference[V, T])
+}
+
+// SymmetricDifference returns a new tree with the keys that are in exactly one of t and other
+// in O(m log(n/m + 1)). Both trees are not modified.
+func (t *Tree[V, T]) SymmetricDifference(other *Tree[V, T]) *Tree[V, T] {
+	return t.combine(other, symmetricDifference[V, T])
+}
+
+// SymmetricDifferenceWith replaces the contents of t with the keys that are in exactly one of t and other
+// in O(m log(n/m + 1)). other is not modified.
+func (t *Tree[V, T]) SymmetricDifferenceWith(other *Tree[V, T]) {
+	t.combineWith(other, symmetricDifference[V, T])
+}
@@ -0,0 +1,184 @@
+package redblack_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+type intTreeSetOp struct {
+	name    string
+	new     func(t, other *intTree) *intTree
+	inPlace func(t, other *intTree)
+	want    func(a, b []int) []int
+}
+
+// setOps returns the set operations of the tree together with a reference implementation on sorted slices.
+func setOps() []intTreeSetOp {
+	contains := func(s []int, v int) bool {
+		_, found := slices.BinarySearch(s, v)
+		return found
+	}
+	filter := func(s []int, keep func(v int) bool) []int {
+		res := []int{}
+		for _, v := range s {
+			if keep(v) {
+				res = append(res, v)
+			}
+		}
+		return res
+	}
+	return []intTreeSetOp{
+		{"Union", (*intTree).Union, (*intTree).UnionWith, func(a, b []int) []int {
+			res := append(slices.Clone(a), filter(b, func(v int) bool { return !contains(a, v) })...)
+			slices.Sort(res)
+			return res
+		}},
+		{"Intersection", (*intTree).Intersection, (*intTree).IntersectionWith, func(a, b []int) []int {
+			return filter(a, func(v int) bool { return contains(b, v) })
+		}},
+		{"Difference", (*intTree).Difference, (*intTree).DifferenceWith, func(a, b []int) []int {
+			return filter(a, func(v int) bool { return !contains(b, v) })
+		}},
+		{"SymmetricDifference", (*intTree).SymmetricDifference, (*intTree).SymmetricDifferenceWith, func(a, b []int) []int {
+			res := append(
+				filter(a, func(v int) bool { return !contains(b, v) }),
+				filter(b, func(v int) bool { return !contains(a, v) })...)
+			slices.Sort(res)
+			return res
+		}},
+	}
+}
+
+func TestTree_SetOperations(t1 *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+	}{
+		{"Empty Trees", []int{}, []int{}},
+		{"Empty Left", []int{}, []int{1, 2, 3}},
+		{"Empty Right", []int{1, 2, 3}, []int{}},
+		{"Equal", []int{1, 2, 3}, []int{1, 2, 3}},
+		{"Disjoint", []int{1, 2, 3}, []int{4, 5, 6}},
+		{"Interleaved", []int{1, 3, 5, 7}, []int{2, 3, 4, 5}},
+		{"Small And Large", []int{50}, rand.Perm(100)},
+	}
+	for _, op := range setOps() {
+		for _, tt := range tests {
+			t1.Run(op.name+"/"+tt.name, func(t1 *testing.T) {
+				a, b := newIntTree(t1, tt.a), newIntTree(t1, tt.b)
+				wantA, wantB := a.ToSortedSlice(), b.ToSortedSlice()
+				want := op.want(wantA, wantB)
+
+				got := op.new(a, b)
+				if keys := got.ToSortedSlice(); !reflect.DeepEqual(keys, want) {
+					t1.Errorf("%s() = %v, want %v", op.name, keys, want)
+				}
+				checkInvariants(t1, op.name+"()", got)
+
+				op.inPlace(a, b)
+				if keys := a.ToSortedSlice(); !reflect.DeepEqual(keys, want) {
+					t1.Errorf("%sWith() = %v, want %v", op.name, keys, want)
+				}
+				if a.Len() != len(want) {
+					t1.Errorf("%sWith() Len() = %v, want %v", op.name, a.Len(), len(want))
+				}
+				checkInvariants(t1, op.name+"With()", a)
+				if keys := b.ToSortedSlice(); !reflect.DeepEqual(keys, wantB) {
+					t1.Errorf("other = %v after %sWith(), want %v", keys, op.name, wantB)
+				}
+			})
+		}
+	}
+}
+
+func TestTree_SetOperationsRandom(t1 *testing.T) {
+	for _, op := range setOps() {
+		t1.Run(op.name, func(t1 *testing.T) {
+			for i := 0; i < 100; i++ {
+				n, m := rand.Intn(1<<rand.Intn(10)), rand.Intn(1<<rand.Intn(10))
+				a, b := newIntTree(t1, rand.Perm(2 * n)[:n]), newIntTree(t1, rand.Perm(2 * m)[:m])
+				wantA, wantB := a.ToSortedSlice(), b.ToSortedSlice()
+				want := op.want(wantA, wantB)
+
+				got := op.new(a, b)
+				if keys := got.ToSortedSlice(); !slices.Equal(keys, want) {
+					t1.Fatalf("%s() = %v, want %v", op.name, keys, want)
+				}
+				if got.Len() != len(want) {
+					t1.Errorf("%s() Len() = %v, want %v", op.name, got.Len(), len(want))
+				}
+				checkInvariants(t1, op.name+"()", got)
+
+				// modifying the result must not affect the inputs
+				for _, v := range want[:len(want)/2] {
+					got.Delete(v)
+				}
+				if keys := a.ToSortedSlice(); !slices.Equal(keys, wantA) {
+					t1.Fatalf("tree = %v after modifying %s(), want %v", keys, op.name, wantA)
+				}
+
+				op.inPlace(a, b)
+				if keys := a.ToSortedSlice(); !slices.Equal(keys, want) {
+					t1.Fatalf("%sWith() = %v, want %v", op.name, keys, want)
+				}
+				checkInvariants(t1, op.name+"With()", a)
+				if keys := b.ToSortedSlice(); !slices.Equal(keys, wantB) {
+					t1.Fatalf("other = %v after %sWith(), want %v", keys, op.name, wantB)
+				}
+				checkInvariants(t1, "other", b)
+			}
+		})
+	}
+}
+
+func TestTree_SetOperationsWithItself(t1 *testing.T) {
+	values := rand.Perm(100)
+	for _, op := range setOps() {
+		t1.Run(op.name, func(t1 *testing.T) {
+			t := newIntTree(t1, values)
+			keys := t.ToSortedSlice()
+			want := op.want(keys, keys)
+			op.inPlace(t, t)
+			if got := t.ToSortedSlice(); !slices.Equal(got, want) {
+				t1.Errorf("%sWith() = %v, want %v", op.name, got, want)
+			}
+			checkInvariants(t1, op.name+"With()", t)
+		})
+	}
+}
+
+func TestTree_SetOpsConcurrentReaders(t1 *testing.T) {
+	a := newIntTree(t1, rand.Perm(500))
+	b := newIntTree(t1, rand.Perm(1000)[:300])
+	want := a.Union(b).ToSortedSlice()
+
+	// set operations only read their inputs, so they may run concurrently on the same trees
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if got := a.Union(b).ToSortedSlice(); !slices.Equal(got, want) {
+					t1.Errorf("Union() = %v, want %v", got, want)
+				}
+				a.Intersection(b)
+				b.Split(250)
+				a.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the inputs still copy their shared nodes before modifying them
+	u := a.Union(b)
+	a.Insert(redblack.Ordered(5000))
+	if got := u.ToSortedSlice(); !slices.Equal(got, want) {
+		t1.Errorf("Union() = %v after modifying the input, want %v", got, want)
+	}
+}
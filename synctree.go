@@ -0,0 +1,384 @@
+package redblack
+
+import (
+	"io"
+	"iter"
+	"sync"
+)
+
+// SyncTree is a red-black tree that is safe for concurrent use by multiple goroutines.
+// Reading methods share a read lock, modifying methods hold the write lock.
+//
+// The iterators returned by SyncTree hold the read lock while they are iterated. The loop body must
+// therefore not call any method of the SyncTree, not even a reading one like Len or Search: a
+// sync.RWMutex must not be read-locked recursively, and the loop deadlocks as soon as a writer waits
+// for the lock. For long-running reads or reads that combine several methods, take a Snapshot instead,
+// which can be read without any locking, or use View.
+//
+// SyncTree wraps the methods of Tree that search, insert and delete keys, iterate, print, validate
+// and encode the tree, including WriteDOT. Cursors, splitting, joining, set operations and decoding are
+// not wrapped; use them within View or Do, or on a Snapshot. GetTreeLevels is not wrapped either, since
+// the nodes it returns belong to the tree and must not be read outside of the lock.
+//
+// The zero value is an empty tree ready to use. A SyncTree must not be copied after first use.
+type SyncTree[V any, T Orderable[V]] struct {
+	mu   sync.RWMutex
+	tree Tree[V, T]
+}
+
+// NewSyncTree creates a new concurrency-safe tree with the contents of tree in O(1).
+// The trees do not affect each other afterwards, so tree may still be used on its own.
+func NewSyncTree[V any, T Orderable[V]](tree *Tree[V, T]) *SyncTree[V, T] {
+	s := new(SyncTree[V, T])
+	if tree != nil {
+		s.tree = *tree.Snapshot().Tree()
+	}
+	return s
+}
+
+// View calls f with the underlying tree while holding the read lock.
+// f must not modify the tree or keep a reference to it after returning.
+func (s *SyncTree[V, T]) View(f func(t *Tree[V, T])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f(&s.tree)
+}
+
+// Do calls f with the underlying tree while holding the write lock.
+// All modifications done by f appear atomic to other goroutines. f must not keep a reference to the tree
+// after returning.
+func (s *SyncTree[V, T]) Do(f func(t *Tree[V, T])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(&s.tree)
+}
+
+// Snapshot returns the current state of the tree as a PersistentTree in O(1).
+// The snapshot can be read without locking while the tree is modified.
+func (s *SyncTree[V, T]) Snapshot() *PersistentTree[V, T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Snapshot()
+}
+
+// InsertIfAbsent is GetOrInsert.
+//
+// Deprecated: use GetOrInsert.
+func (s *SyncTree[V, T]) InsertIfAbsent(item T) (actual T, inserted bool) {
+	return s.GetOrInsert(item)
+}
+
+// GetOrInsert returns the item that is stored for the key of item and false if the key exists already.
+// Otherwise item is added to the tree and returned with true.
+func (s *SyncTree[V, T]) GetOrInsert(item T) (actual T, inserted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.GetOrInsert(item)
+}
+
+// Update replaces the item stored for the key k by f applied to it. f is called while holding the write lock,
+// so concurrent updates of the same key do not get lost.
+// Returns KeyDoesNotExistError if k is not in the tree and KeyChangedError if the item returned by f has
+// a different key.
+func (s *SyncTree[V, T]) Update(k V, f func(item T) T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.Update(k, f)
+}
+
+// ReplaceOrInsert adds the item to the tree or replaces the item that is stored for its key.
+// Returns the replaced item and true if the key already existed.
+func (s *SyncTree[V, T]) ReplaceOrInsert(item T) (old T, replaced bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.ReplaceOrInsert(item)
+}
+
+// DeleteAndReturn removes the key k from the tree and returns the item that was stored for it.
+// Returns false if the key is not found.
+func (s *SyncTree[V, T]) DeleteAndReturn(k V) (item T, deleted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.Remove(k)
+}
+
+// Search returns true if the key is found in the tree and the value of the key.
+// If the key is not found, the second return value is the key itself.
+func (s *SyncTree[V, T]) Search(k V) (bool, V) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Search(k)
+}
+
+// SearchUpper returns the value of the smallest key in the tree that is greater than or equal to the given key.
+// Returns KeyDoesNotExistError if k > i for all i in the tree.
+func (s *SyncTree[V, T]) SearchUpper(k V) (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.SearchUpper(k)
+}
+
+// SearchLower returns the value of the largest key in the tree that is less than or equal to the given key.
+// Returns KeyDoesNotExistError if k < i for all i in the tree.
+func (s *SyncTree[V, T]) SearchLower(k V) (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.SearchLower(k)
+}
+
+// Insert adds a new node to the tree if the item is not a duplicate of another item in the tree.
+// Returns KeyExistsError if the key already exists in the tree.
+func (s *SyncTree[V, T]) Insert(item T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.Insert(item)
+}
+
+// Delete removes a node from the tree if the key is found.
+// Returns false if the key is not found.
+func (s *SyncTree[V, T]) Delete(k V) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.Delete(k)
+}
+
+// DeleteMin removes the node with the smallest key from the tree.
+func (s *SyncTree[V, T]) DeleteMin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.DeleteMin()
+}
+
+// DeleteMax removes the node with the largest key from the tree.
+func (s *SyncTree[V, T]) DeleteMax() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.DeleteMax()
+}
+
+// PopMin removes the smallest key from the tree and returns it.
+// Returns false if the tree is empty.
+func (s *SyncTree[V, T]) PopMin() (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.PopMin()
+}
+
+// PopMax removes the largest key from the tree and returns it.
+// Returns false if the tree is empty.
+func (s *SyncTree[V, T]) PopMax() (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.PopMax()
+}
+
+// DeleteAt removes the i-th smallest key from the tree, counting from 0, and returns it.
+// Returns IndexOutOfRangeError if i < 0 or i >= s.Len().
+func (s *SyncTree[V, T]) DeleteAt(i int) (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.DeleteAt(i)
+}
+
+// Height return the height of the tree.
+func (s *SyncTree[V, T]) Height() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Height()
+}
+
+// BlackHeight returns the number of black nodes on any path between the root and a leaf.
+func (s *SyncTree[V, T]) BlackHeight() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.BlackHeight()
+}
+
+// MinDepth returns the number of nodes on the shortest path between the root and a leaf.
+func (s *SyncTree[V, T]) MinDepth() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.MinDepth()
+}
+
+// Len returns the number of nodes in the tree.
+func (s *SyncTree[V, T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Len()
+}
+
+// Min returns the smallest key in the tree, the zero value for an empty tree.
+func (s *SyncTree[V, T]) Min() V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Min()
+}
+
+// MinOk returns the smallest key in the tree.
+// Returns false if the tree is empty.
+func (s *SyncTree[V, T]) MinOk() (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.MinOk()
+}
+
+// Max returns the largest key in the tree, the zero value for an empty tree.
+func (s *SyncTree[V, T]) Max() V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Max()
+}
+
+// MaxOk returns the largest key in the tree.
+// Returns false if the tree is empty.
+func (s *SyncTree[V, T]) MaxOk() (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.MaxOk()
+}
+
+// Select returns the i-th smallest key in the tree, counting from 0.
+// Returns IndexOutOfRangeError if i < 0 or i >= s.Len().
+func (s *SyncTree[V, T]) Select(i int) (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Select(i)
+}
+
+// Rank returns the number of keys in the tree that are less than k.
+func (s *SyncTree[V, T]) Rank(k V) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Rank(k)
+}
+
+// CountRange returns the number of keys k in the tree with lo <= k < hi.
+func (s *SyncTree[V, T]) CountRange(lo, hi V) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.CountRange(lo, hi)
+}
+
+// ToSortedSlice returns a sorted slice of the keys in the tree.
+func (s *SyncTree[V, T]) ToSortedSlice() []V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.ToSortedSlice()
+}
+
+// Walk walks the tree in the specified order and calls the given function for each node while holding the read lock.
+// If the function returns false, the walk is stopped.
+func (s *SyncTree[V, T]) Walk(f func(*Node[V, T]) bool, order WalkOrder) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.tree.Walk(f, order)
+}
+
+// String returns a string representation of the tree.
+func (s *SyncTree[V, T]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.String()
+}
+
+// Fprint draws the tree with boxes for the nodes and writes it to w while holding the read lock.
+func (s *SyncTree[V, T]) Fprint(w io.Writer, opts PrintOptions[V]) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Fprint(w, opts)
+}
+
+// WriteDOT writes the structure of the tree as a Graphviz graph to w like Tree.WriteDOT while holding the read lock.
+func (s *SyncTree[V, T]) WriteDOT(w io.Writer, opts DOTOptions[V]) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.WriteDOT(w, opts)
+}
+
+// Validate checks the structure of the tree like Tree.Validate.
+func (s *SyncTree[V, T]) Validate() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Validate()
+}
+
+// WriteTo writes the keys of the tree in the binary format of Tree.WriteTo to w while holding the read lock.
+func (s *SyncTree[V, T]) WriteTo(w io.Writer) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.WriteTo(w)
+}
+
+// WriteToCodec writes the keys of the tree encoded by c to w like Tree.WriteToCodec.
+func (s *SyncTree[V, T]) WriteToCodec(w io.Writer, c KeyCodec[V]) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.WriteToCodec(w, c)
+}
+
+// MarshalJSON encodes the keys of the tree as a sorted JSON array.
+func (s *SyncTree[V, T]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.MarshalJSON()
+}
+
+// rlocked returns an iterator that holds the read lock while seq is iterated.
+func (s *SyncTree[V, T]) rlocked(seq func(t *Tree[V, T]) iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		seq(&s.tree)(yield)
+	}
+}
+
+// Sorted returns an iterator that yields the keys in the tree in sorted order.
+func (s *SyncTree[V, T]) Sorted() iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.Sorted() })
+}
+
+// Backward returns an iterator that yields the keys in the tree in descending order.
+func (s *SyncTree[V, T]) Backward() iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.Backward() })
+}
+
+// Range returns an iterator that yields the keys k with lo <= k < hi in sorted order.
+func (s *SyncTree[V, T]) Range(lo, hi V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.Range(lo, hi) })
+}
+
+// RangeClosed returns an iterator that yields the keys k with lo <= k <= hi in sorted order.
+func (s *SyncTree[V, T]) RangeClosed(lo, hi V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeClosed(lo, hi) })
+}
+
+// RangeFrom returns an iterator that yields the keys k with lo <= k in sorted order.
+func (s *SyncTree[V, T]) RangeFrom(lo V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeFrom(lo) })
+}
+
+// RangeTo returns an iterator that yields the keys k with k < hi in sorted order.
+func (s *SyncTree[V, T]) RangeTo(hi V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeTo(hi) })
+}
+
+// RangeBackward returns an iterator that yields the keys k with lo <= k < hi in descending order.
+func (s *SyncTree[V, T]) RangeBackward(lo, hi V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeBackward(lo, hi) })
+}
+
+// RangeClosedBackward returns an iterator that yields the keys k with lo <= k <= hi in descending order.
+func (s *SyncTree[V, T]) RangeClosedBackward(lo, hi V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeClosedBackward(lo, hi) })
+}
+
+// RangeFromBackward returns an iterator that yields the keys k with lo <= k in descending order.
+func (s *SyncTree[V, T]) RangeFromBackward(lo V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeFromBackward(lo) })
+}
+
+// RangeToBackward returns an iterator that yields the keys k with k < hi in descending order.
+func (s *SyncTree[V, T]) RangeToBackward(hi V) iter.Seq[V] {
+	return s.rlocked(func(t *Tree[V, T]) iter.Seq[V] { return t.RangeToBackward(hi) })
+}
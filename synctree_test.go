@@ -0,0 +1,225 @@
+package redblack_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+// payload is an Orderable that carries data next to its key.
+type payload struct {
+	key  int
+	data string
+}
+
+func (p payload) CompareTo(other int) int {
+	return p.key - other
+}
+
+func (p payload) Value() int {
+	return p.key
+}
+
+func TestSyncTree_Concurrent(t1 *testing.T) {
+	s := redblack.NewSyncTree(newIntTree(t1, []int{}))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				// every writer inserts its own keys, so all inserts succeed
+				if err := s.Insert(redblack.Ordered(w*1000 + i)); err != nil {
+					t1.Errorf("Insert() error = %v", err)
+				}
+				if i%2 == 0 {
+					s.Delete(w*1000 + i)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				prev := -1
+				for v := range s.Sorted() {
+					if v <= prev {
+						t1.Errorf("Sorted() yields %v after %v", v, prev)
+					}
+					prev = v
+				}
+				s.Len()
+				s.Search(rand.Intn(4000))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Len() != 4*125 {
+		t1.Errorf("Len() = %v, want %v", s.Len(), 4*125)
+	}
+	s.View(func(t *redblack.Tree[int, redblack.Orderable[int]]) {
+		if !redblack.CheckNoRedRed(t) || !redblack.CheckLeftLeaning(t) || !redblack.CheckSize(t) {
+			t1.Errorf("concurrent modifications resulted in an invalid tree")
+		}
+	})
+}
+
+func TestSyncTree_InsertIfAbsent(t1 *testing.T) {
+	s := new(redblack.SyncTree[int, payload])
+	tests := []struct {
+		name         string
+		item         payload
+		wantActual   payload
+		wantInserted bool
+	}{
+		{"Insert", payload{1, "a"}, payload{1, "a"}, true},
+		{"Insert Another", payload{2, "b"}, payload{2, "b"}, true},
+		{"Existing Key", payload{1, "c"}, payload{1, "a"}, false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			actual, inserted := s.InsertIfAbsent(tt.item)
+			if actual != tt.wantActual || inserted != tt.wantInserted {
+				t1.Errorf("InsertIfAbsent() = (%v, %v), want (%v, %v)", actual, inserted, tt.wantActual, tt.wantInserted)
+			}
+		})
+	}
+}
+
+func TestSyncTree_DeleteAndReturn(t1 *testing.T) {
+	tree, err := redblack.NewTree([]payload{{1, "a"}, {2, "b"}, {3, "c"}}, false)
+	if err != nil {
+		t1.Fatalf("redblack.NewTree() error = %v", err)
+	}
+	s := redblack.NewSyncTree(tree)
+	tests := []struct {
+		name        string
+		k           int
+		wantItem    payload
+		wantDeleted bool
+		want        []int
+	}{
+		{"Delete", 2, payload{2, "b"}, true, []int{1, 3}},
+		{"Delete Again", 2, payload{}, false, []int{1, 3}},
+		{"Missing Key", 4, payload{}, false, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			item, deleted := s.DeleteAndReturn(tt.k)
+			if item != tt.wantItem || deleted != tt.wantDeleted {
+				t1.Errorf("DeleteAndReturn() = (%v, %v), want (%v, %v)", item, deleted, tt.wantItem, tt.wantDeleted)
+			}
+			if got := s.ToSortedSlice(); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("ToSortedSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the original tree is not affected by the SyncTree
+	if got := tree.ToSortedSlice(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t1.Errorf("original tree = %v, want %v", got, []int{1, 2, 3})
+	}
+}
+
+func TestSyncTree_Do(t1 *testing.T) {
+	s := redblack.NewSyncTree(newIntTree(t1, []int{1, 2, 3}))
+	s.Do(func(t *redblack.Tree[int, redblack.Orderable[int]]) {
+		t.DeleteMin()
+		_ = t.Insert(redblack.Ordered(4))
+	})
+	snap := s.Snapshot()
+	s.Delete(4)
+
+	if got := snap.ToSortedSlice(); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t1.Errorf("snapshot = %v, want %v", got, []int{2, 3, 4})
+	}
+	if got := s.ToSortedSlice(); !reflect.DeepEqual(got, []int{2, 3}) {
+		t1.Errorf("ToSortedSlice() = %v, want %v", got, []int{2, 3})
+	}
+}
+
+func TestSyncTree_Encode(t1 *testing.T) {
+	tree := newIntTree(t1, rand.Perm(50))
+	s := redblack.NewSyncTree(tree)
+
+	var want, got bytes.Buffer
+	if _, err := tree.WriteTo(&want); err != nil {
+		t1.Fatalf("Tree.WriteTo() error = %v", err)
+	}
+	if _, err := s.WriteTo(&got); err != nil || !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t1.Errorf("WriteTo() = %x, %v, want %x", got.Bytes(), err, want.Bytes())
+	}
+
+	wantJSON, _ := json.Marshal(tree)
+	if gotJSON, err := json.Marshal(s); err != nil || !bytes.Equal(gotJSON, wantJSON) {
+		t1.Errorf("json.Marshal() = %s, %v, want %s", gotJSON, err, wantJSON)
+	}
+
+	want.Reset()
+	got.Reset()
+	opts := redblack.PrintOptions[int]{ASCII: true}
+	tree.Fprint(&want, opts)
+	if err := s.Fprint(&got, opts); err != nil || got.String() != want.String() {
+		t1.Errorf("Fprint() = %q, %v, want %q", got.String(), err, want.String())
+	}
+
+	want.Reset()
+	got.Reset()
+	tree.WriteDOT(&want, redblack.DOTOptions[int]{})
+	if err := s.WriteDOT(&got, redblack.DOTOptions[int]{}); err != nil || got.String() != want.String() {
+		t1.Errorf("WriteDOT() = %q, %v, want %q", got.String(), err, want.String())
+	}
+
+	if err := s.Validate(); err != nil {
+		t1.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestSyncTree_GetOrInsert(t1 *testing.T) {
+	s := new(redblack.SyncTree[int, payload])
+	if actual, inserted := s.GetOrInsert(payload{1, "a"}); !inserted || actual != (payload{1, "a"}) {
+		t1.Errorf("GetOrInsert() = %v, %v, want %v, true", actual, inserted, payload{1, "a"})
+	}
+	if actual, inserted := s.GetOrInsert(payload{1, "b"}); inserted || actual != (payload{1, "a"}) {
+		t1.Errorf("GetOrInsert() = %v, %v, want %v, false", actual, inserted, payload{1, "a"})
+	}
+}
+
+func TestSyncTree_Update(t1 *testing.T) {
+	s := redblack.NewSyncTree(newPayloadTree(t1, []int{1, 2}))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := s.Update(1, func(p payload) payload { p.data += "x"; return p }); err != nil {
+					t1.Errorf("Update() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.View(func(t *redblack.Tree[int, payload]) {
+		if got := storedItem(t, 1); got.data != "1"+strings.Repeat("x", 800) {
+			t1.Errorf("Update() lost updates, data has length %d, want %d", len(got.data), 801)
+		}
+	})
+	if err := s.Update(3, func(p payload) payload { return p }); err != redblack.KeyDoesNotExistError {
+		t1.Errorf("Update() error = %v, want %v", err, redblack.KeyDoesNotExistError)
+	}
+	if err := s.Update(2, func(p payload) payload { p.key = 4; return p }); err != redblack.KeyChangedError {
+		t1.Errorf("Update() error = %v, want %v", err, redblack.KeyChangedError)
+	}
+}
@@ -1,10 +1,15 @@
 package redblack
 
-import "iter"
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
 
 type Tree[V any, T Orderable[V]] struct {
-	root *Node[V, T]
-	num  int
+	root  *Node[V, T]
+	num   int
+	owner *owner
 }
 
 // WalkOrder specifies the order in which the nodes are visited when walking the tree.
@@ -20,8 +25,28 @@ const (
 	POSTORDER
 	// LEVELORDER visits the nodes level by level from left to right.
 	LEVELORDER
+	// REVERSE_INORDER visits all right children (larger keys), then the current node, then all left children (smaller keys).
+	// This results in keys being visited in descending order.
+	REVERSE_INORDER
 )
 
+// mutation returns the owner token that the tree uses to modify its nodes in place.
+// A new token is created after the nodes have been shared, e.g., by Snapshot.
+func (t *Tree[V, T]) mutation() *owner {
+	if t.owner == nil || t.owner.shared.Load() {
+		t.owner = newOwner[V, T]()
+	}
+	return t.owner
+}
+
+// share marks the nodes of the tree as shared with another tree, so that the tree copies them before
+// modifying them from now on. The tree itself is not written, so share is safe for concurrent readers.
+func (t *Tree[V, T]) share() {
+	if t.owner != nil {
+		t.owner.shared.Store(true)
+	}
+}
+
 // Search returns true if the key is found in the tree and the value of the key.
 // If the key is not found, the second return value is the key itself.
 func (t *Tree[V, T]) Search(k V) (bool, V) {
@@ -51,45 +76,111 @@ func (t *Tree[V, T]) SearchLower(k V) (V, error) {
 }
 
 // Insert adds a new node to the tree if the item is not a duplicate of another item in the tree.
-// Returns KeyExistsError if the key already exists in the tree.
+// Returns KeyExistsError if the key already exists in the tree; the tree is left unchanged in that case.
 func (t *Tree[V, T]) Insert(item T) error {
-	newNode, err := t.root.insert(item)
-	if err != nil {
-		return err
+	if _, inserted := t.GetOrInsert(item); !inserted {
+		return KeyExistsError
+	}
+	return nil
+}
+
+// ReplaceOrInsert adds the item to the tree or replaces the item that is stored for its key, in a single descent.
+// Returns the replaced item and true if the key already existed.
+func (t *Tree[V, T]) ReplaceOrInsert(item T) (old T, replaced bool) {
+	t.root, old, replaced = t.root.put(t.mutation(), item, true)
+	t.root.red = false
+	if !replaced {
+		t.num++
+	}
+	return old, replaced
+}
+
+// GetOrInsert returns the item that is stored for the key of item and false if the key exists already.
+// Otherwise item is added to the tree and returned with true. The tree is descended only once.
+func (t *Tree[V, T]) GetOrInsert(item T) (actual T, inserted bool) {
+	root, old, found := t.root.put(t.mutation(), item, false)
+	if found {
+		return old, false
 	}
 	t.num++
-	t.root = newNode
+	t.root = root
 	t.root.red = false
+	return item, true
+}
+
+// Update replaces the item stored for the key k by f applied to it, in a single descent.
+// Returns KeyDoesNotExistError if k is not in the tree and KeyChangedError if the item returned by f has
+// a different key. The tree is left unchanged in both cases.
+func (t *Tree[V, T]) Update(k V, f func(item T) T) error {
+	root, err := t.root.modify(t.mutation(), k, f)
+	if err != nil {
+		return err
+	}
+	t.root = root
 	return nil
 }
 
 // Delete removes a node from the tree if the key is found.
 // Returns false if the key is not found.
-func (t *Tree[V, T]) Delete(v V) (success bool) {
-	if t.root == nil {
-		return false
-	}
+func (t *Tree[V, T]) Delete(v V) bool {
+	_, found := t.Remove(v)
+	return found
+}
 
-	if found, _ := t.Search(v); !found {
-		return false
+// Remove removes the key k from the tree and returns the item that was stored for it.
+// Returns false if the key is not found.
+func (t *Tree[V, T]) Remove(k V) (item T, found bool) {
+	if t.root == nil {
+		return item, false
 	}
-
-	t.root, success = t.root.delete(v)
+	t.root, item, found = t.root.delete(t.mutation(), k)
 	if t.root != nil {
 		t.root.red = false
 	}
-	if success {
+	if found {
 		t.num--
 	}
-	return
+	return item, found
 }
 
 // DeleteMin removes the node with the smallest key from the tree.
 func (t *Tree[V, T]) DeleteMin() {
+	t.PopMin()
+}
+
+// DeleteMax removes the node with the largest key from the tree.
+func (t *Tree[V, T]) DeleteMax() {
+	t.PopMax()
+}
+
+// PopMin removes the smallest key from the tree and returns it.
+// Returns false if the tree is empty.
+func (t *Tree[V, T]) PopMin() (v V, ok bool) {
+	if t.root == nil {
+		return v, false
+	}
+	var item T
+	t.root, item = t.root.deleteMin(t.mutation())
 	if t.root != nil {
-		t.root = t.root.deleteMin()
-		t.num--
+		t.root.red = false
+	}
+	t.num--
+	return item.Value(), true
+}
+
+// PopMax removes the largest key from the tree and returns it.
+// Returns false if the tree is empty.
+func (t *Tree[V, T]) PopMax() (v V, ok bool) {
+	if t.root == nil {
+		return v, false
 	}
+	var item T
+	t.root, item = t.root.deleteMax(t.mutation())
+	if t.root != nil {
+		t.root.red = false
+	}
+	t.num--
+	return item.Value(), true
 }
 
 // Creates a new red-black tree from a slice of Orderable items.
@@ -107,10 +198,78 @@ func NewTree[V any, T Orderable[V]](items []T, ignore_duplicates bool) (*Tree[V,
 	return tree, nil
 }
 
-// Height return the height of the tree.
-// The height of a tree is the number of edges on the longest path between the root and a leaf.
+// Creates a new red-black tree from a slice of Orderable items that are sorted in ascending order.
+// The tree is built directly in O(n) instead of inserting the items one by one.
+// If ignore_duplicates is true, duplicate items will be ignored otherwise a KeyExistsError will be returned.
+// Returns NotSortedError if the items are not sorted.
+func NewTreeFromSorted[V any, T Orderable[V]](items []T, ignore_duplicates bool) (*Tree[V, T], error) {
+	unique := items
+	for i := 1; i < len(items); i++ {
+		c := items[i-1].CompareTo(items[i].Value())
+		if c > 0 {
+			return nil, NotSortedError
+		}
+		if c == 0 {
+			if !ignore_duplicates {
+				return nil, KeyExistsError
+			}
+			if len(unique) == len(items) {
+				// copy the items on the first duplicate, so that the input is not modified
+				unique = slices.Clone(items[:i])
+			}
+			continue
+		}
+		if len(unique) < len(items) {
+			unique = append(unique, items[i])
+		}
+	}
+
+	tree := new(Tree[V, T])
+	tree.num = len(unique)
+	// all levels but the deepest one are complete; its nodes are red unless it is complete as well
+	h := bits.Len(uint(len(unique)))
+	redDepth := h - 1
+	if len(unique) == 1<<h-1 {
+		redDepth = -1
+	}
+	tree.root = buildSorted(tree.mutation(), unique, 0, redDepth)
+	if tree.root != nil {
+		tree.root.red = false
+	}
+	return tree, nil
+}
+
+// FromSeq creates a new red-black tree from the items of a sequence.
+// If the items are sorted in ascending order, the tree is built in O(n) as in NewTreeFromSorted, otherwise
+// the items are inserted one by one as in NewTree.
+// If ignore_duplicates is true, duplicate items will be ignored otherwise a KeyExistsError will be returned.
+func FromSeq[V any, T Orderable[V]](seq iter.Seq[T], ignore_duplicates bool) (*Tree[V, T], error) {
+	items := slices.Collect(seq)
+	tree, err := NewTreeFromSorted(items, ignore_duplicates)
+	if err == NotSortedError {
+		return NewTree(items, ignore_duplicates)
+	}
+	return tree, err
+}
+
+// Height return the height of the tree in O(1).
+// The height of a tree is the number of nodes on the longest path between the root and a leaf, 0 for an empty tree.
 func (t *Tree[V, T]) Height() int {
-	return <-t.root.height()
+	return height(t.root)
+}
+
+// BlackHeight returns the number of black nodes on any path between the root and a leaf in O(log n).
+func (t *Tree[V, T]) BlackHeight() int {
+	return t.root.blackHeight()
+}
+
+// MinDepth returns the number of nodes on the shortest path between the root and a leaf in O(1),
+// 0 for an empty tree.
+func (t *Tree[V, T]) MinDepth() int {
+	if t.root == nil {
+		return 0
+	}
+	return int(t.root.minDepth)
 }
 
 // Len returns the number of nodes in the tree.
@@ -118,14 +277,68 @@ func (t *Tree[V, T]) Len() int {
 	return t.num
 }
 
-// Min returns the smallest key in the tree.
+// Min returns the smallest key in the tree, the zero value for an empty tree.
+// Use MinOk to tell an empty tree from a tree whose smallest key is the zero value.
 func (t *Tree[V, T]) Min() V {
-	return t.root.min().Value()
+	v, _ := t.MinOk()
+	return v
 }
 
-// Max returns the largest key in the tree.
+// Max returns the largest key in the tree, the zero value for an empty tree.
+// Use MaxOk to tell an empty tree from a tree whose largest key is the zero value.
 func (t *Tree[V, T]) Max() V {
-	return t.root.max().Value()
+	v, _ := t.MaxOk()
+	return v
+}
+
+// MinOk returns the smallest key in the tree.
+// Returns false if the tree is empty.
+func (t *Tree[V, T]) MinOk() (v V, ok bool) {
+	if t.root == nil {
+		return v, false
+	}
+	return t.root.min().Value(), true
+}
+
+// MaxOk returns the largest key in the tree.
+// Returns false if the tree is empty.
+func (t *Tree[V, T]) MaxOk() (v V, ok bool) {
+	if t.root == nil {
+		return v, false
+	}
+	return t.root.max().Value(), true
+}
+
+// Select returns the i-th smallest key in the tree, counting from 0.
+// Returns IndexOutOfRangeError if i < 0 or i >= t.Len().
+func (t *Tree[V, T]) Select(i int) (V, error) {
+	if n := t.root.selectAt(i); n != nil {
+		return n.Value(), nil
+	}
+	var zero V
+	return zero, IndexOutOfRangeError
+}
+
+// Rank returns the number of keys in the tree that are less than k.
+// If k is in the tree, this is the index of k in the sorted order of the keys.
+func (t *Tree[V, T]) Rank(k V) int {
+	return t.root.rank(k)
+}
+
+// DeleteAt removes the i-th smallest key from the tree, counting from 0, and returns it.
+// Returns IndexOutOfRangeError if i < 0 or i >= t.Len().
+func (t *Tree[V, T]) DeleteAt(i int) (V, error) {
+	v, err := t.Select(i)
+	if err != nil {
+		return v, err
+	}
+	t.Delete(v)
+	return v, nil
+}
+
+// CountRange returns the number of keys k in the tree with lo <= k < hi.
+func (t *Tree[V, T]) CountRange(lo, hi V) int {
+	return max(t.root.rank(hi)-t.root.rank(lo), 0)
 }
 
 // Returns a sorted slice of the keys in the tree.
@@ -142,7 +355,7 @@ func (t *Tree[V, T]) ToSortedSlice() []V {
 }
 
 // Returns each level of the tree as a slice of nodes.
-// Ordered from root to leaves.
+// Ordered from root to leaves. An empty tree has no levels.
 func (t *Tree[V, T]) GetTreeLevels() [][]*Node[V, T] {
 	h := t.Height()
 	level := make([][]*Node[V, T], h)
@@ -168,7 +381,7 @@ func (t *Tree[V, T]) GetTreeLevels() [][]*Node[V, T] {
 }
 
 // Returns an iterator that yields the keys in the tree in sorted order.
-func (t Tree[V, T]) Sorted() iter.Seq[V] {
+func (t *Tree[V, T]) Sorted() iter.Seq[V] {
 	return func(yield func(V) bool) {
 		f := func(n *Node[V, T]) bool {
 			if n != nil {
@@ -180,13 +393,81 @@ func (t Tree[V, T]) Sorted() iter.Seq[V] {
 	}
 }
 
+// Returns an iterator that yields the keys in the tree in descending order.
+func (t *Tree[V, T]) Backward() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		f := func(n *Node[V, T]) bool {
+			if n != nil {
+				return yield(n.Value())
+			}
+			return true
+		}
+		t.Walk(f, REVERSE_INORDER)
+	}
+}
+
+// Range returns an iterator that yields the keys k with lo <= k < hi in sorted order.
+func (t *Tree[V, T]) Range(lo, hi V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{key: lo, inclusive: true}, bound[V]{key: hi}, false)
+}
+
+// RangeClosed returns an iterator that yields the keys k with lo <= k <= hi in sorted order.
+func (t *Tree[V, T]) RangeClosed(lo, hi V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{key: lo, inclusive: true}, bound[V]{key: hi, inclusive: true}, false)
+}
+
+// RangeFrom returns an iterator that yields the keys k with lo <= k in sorted order.
+func (t *Tree[V, T]) RangeFrom(lo V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{key: lo, inclusive: true}, bound[V]{unbounded: true}, false)
+}
+
+// RangeTo returns an iterator that yields the keys k with k < hi in sorted order.
+func (t *Tree[V, T]) RangeTo(hi V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{unbounded: true}, bound[V]{key: hi}, false)
+}
+
+// RangeBackward returns an iterator that yields the keys k with lo <= k < hi in descending order.
+func (t *Tree[V, T]) RangeBackward(lo, hi V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{key: lo, inclusive: true}, bound[V]{key: hi}, true)
+}
+
+// RangeClosedBackward returns an iterator that yields the keys k with lo <= k <= hi in descending order.
+func (t *Tree[V, T]) RangeClosedBackward(lo, hi V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{key: lo, inclusive: true}, bound[V]{key: hi, inclusive: true}, true)
+}
+
+// RangeFromBackward returns an iterator that yields the keys k with lo <= k in descending order.
+func (t *Tree[V, T]) RangeFromBackward(lo V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{key: lo, inclusive: true}, bound[V]{unbounded: true}, true)
+}
+
+// RangeToBackward returns an iterator that yields the keys k with k < hi in descending order.
+func (t *Tree[V, T]) RangeToBackward(hi V) iter.Seq[V] {
+	return t.rangeSeq(bound[V]{unbounded: true}, bound[V]{key: hi}, true)
+}
+
+func (t *Tree[V, T]) rangeSeq(lo, hi bound[V], backward bool) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		f := func(n *Node[V, T]) bool {
+			return yield(n.Value())
+		}
+		if backward {
+			t.root.walkRangeBackward(lo, hi, f)
+		} else {
+			t.root.walkRange(lo, hi, f)
+		}
+	}
+}
+
 // Walks the tree in the specified order and calls the given function for each node.
 // If the function returns false, the walk is stopped.
-// The order can be INORDER, PREORDER, POSTORDER or LEVELORDER.
+// The order can be INORDER, REVERSE_INORDER, PREORDER, POSTORDER or LEVELORDER.
 func (t *Tree[V, T]) Walk(f func(*Node[V, T]) bool, order WalkOrder) {
 	switch order {
 	case INORDER:
 		t.root.walkInOrder(f)
+	case REVERSE_INORDER:
+		t.root.walkReverseInOrder(f)
 	case PREORDER:
 		t.root.walkPreOrder(f)
 	case POSTORDER:
@@ -233,6 +514,18 @@ func (t *Tree[V, T]) checkBlackHeight() (uint, bool) {
 	return blackHeightStack[0], blackHeightSame
 }
 
+func (t *Tree[V, T]) checkSize() bool {
+	sizeCorrect := true
+	f := func(n *Node[V, T]) bool {
+		if n != nil && n.size != size(n.left)+size(n.right)+1 {
+			sizeCorrect = false
+		}
+		return true
+	}
+	t.root.walkPostOrder(f)
+	return sizeCorrect && size(t.root) == t.num
+}
+
 func (t *Tree[V, T]) checkLeftLeaning() bool {
 	leftLeaning := true
 	f := func(n *Node[V, T]) bool {
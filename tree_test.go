@@ -2,15 +2,20 @@ package redblack_test
 
 import (
 	"fmt"
+	"iter"
 	"math/rand"
 	"reflect"
 	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/gregorgebhardt/redblack"
 )
 
+type intTree = redblack.Tree[int, redblack.Orderable[int]]
+
 func TestTree_NewTree(t1 *testing.T) {
 	tests := []struct {
 		name              string
@@ -52,6 +57,9 @@ func TestTree_NewTree(t1 *testing.T) {
 			if !redblack.CheckLeftLeaning(t) {
 				t1.Errorf("NewTree() resulted in a right-leaning tree")
 			}
+			if !redblack.CheckSize(t) {
+				t1.Errorf("NewTree() resulted in wrong subtree sizes")
+			}
 		})
 	}
 }
@@ -138,6 +146,211 @@ func TestTree_Insert(t1 *testing.T) {
 	}
 }
 
+func TestTree_InsertExisting(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+	}{
+		{"One Element", []int{1}},
+		{"Three Elements", []int{1, 2, 3}},
+		{"Ascending", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}},
+		{"Random Elements", rand.Perm(500)},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := new(intTree)
+			for _, v := range tt.values {
+				if err := t.Insert(redblack.Ordered(v)); err != nil {
+					t1.Fatalf("Insert() error = %v", err)
+				}
+			}
+			want := t.String()
+			for i := 0; i < 5*len(tt.values); i++ {
+				k := tt.values[rand.Intn(len(tt.values))]
+				if err := t.Insert(redblack.Ordered(k)); err != redblack.KeyExistsError {
+					t1.Fatalf("Insert(%v) error = %v, want %v", k, err, redblack.KeyExistsError)
+				}
+				if err := t.Validate(); err != nil {
+					t1.Fatalf("Validate() after Insert(%v) error = %v", k, err)
+				}
+			}
+			if got := t.String(); got != want {
+				t1.Errorf("failed inserts changed the tree from\n%v\nto\n%v", want, got)
+			}
+			if t.Len() != len(tt.values) {
+				t1.Errorf("Len() = %v, want %v", t.Len(), len(tt.values))
+			}
+		})
+	}
+}
+
+func TestTree_InsertExistingAfterSnapshot(t1 *testing.T) {
+	t := new(intTree)
+	for _, v := range rand.Perm(200) {
+		t.Insert(redblack.Ordered(v))
+	}
+	p := t.Snapshot()
+	want := p.Tree().String()
+	for _, v := range rand.Perm(200) {
+		if err := t.Insert(redblack.Ordered(v)); err != redblack.KeyExistsError {
+			t1.Fatalf("Insert(%v) error = %v, want %v", v, err, redblack.KeyExistsError)
+		}
+		if err := t.Validate(); err != nil {
+			t1.Fatalf("Validate() after Insert(%v) error = %v", v, err)
+		}
+	}
+	if got := p.Tree().String(); got != want {
+		t1.Errorf("failed inserts changed the snapshot from\n%v\nto\n%v", want, got)
+	}
+	if got := t.String(); got != want {
+		t1.Errorf("failed inserts changed the tree from\n%v\nto\n%v", want, got)
+	}
+}
+
+// newPayloadTree returns a tree with an item for each key whose data is the formatted key.
+func newPayloadTree(t1 *testing.T, keys []int) *redblack.Tree[int, payload] {
+	t1.Helper()
+	t := new(redblack.Tree[int, payload])
+	for _, k := range keys {
+		if err := t.Insert(payload{k, strconv.Itoa(k)}); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	return t
+}
+
+// storedItem returns the item stored for the key k.
+func storedItem(t *redblack.Tree[int, payload], k int) (item payload) {
+	t.Update(k, func(p payload) payload {
+		item = p
+		return p
+	})
+	return item
+}
+
+func TestTree_ReplaceOrInsert(t1 *testing.T) {
+	tests := []struct {
+		name         string
+		keys         []int
+		item         payload
+		wantOld      payload
+		wantReplaced bool
+		wantLen      int
+	}{
+		{"Empty Tree", []int{}, payload{1, "new"}, payload{}, false, 1},
+		{"New Key", []int{1, 2, 3}, payload{4, "new"}, payload{}, false, 4},
+		{"Existing Key", []int{1, 2, 3}, payload{2, "new"}, payload{2, "2"}, true, 3},
+		{"Existing Root", []int{1, 2, 3, 4, 5}, payload{4, "new"}, payload{4, "4"}, true, 5},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newPayloadTree(t1, tt.keys)
+			old, replaced := t.ReplaceOrInsert(tt.item)
+			if old != tt.wantOld || replaced != tt.wantReplaced {
+				t1.Errorf("ReplaceOrInsert() = %v, %v, want %v, %v", old, replaced, tt.wantOld, tt.wantReplaced)
+			}
+			if got := storedItem(t, tt.item.key); got != tt.item {
+				t1.Errorf("stored item = %v, want %v", got, tt.item)
+			}
+			if t.Len() != tt.wantLen {
+				t1.Errorf("Len() = %v, want %v", t.Len(), tt.wantLen)
+			}
+			if err := t.Validate(); err != nil {
+				t1.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTree_GetOrInsert(t1 *testing.T) {
+	tests := []struct {
+		name         string
+		keys         []int
+		item         payload
+		wantActual   payload
+		wantInserted bool
+		wantLen      int
+	}{
+		{"Empty Tree", []int{}, payload{1, "new"}, payload{1, "new"}, true, 1},
+		{"New Key", []int{1, 2, 3}, payload{4, "new"}, payload{4, "new"}, true, 4},
+		{"Existing Key", []int{1, 2, 3}, payload{2, "new"}, payload{2, "2"}, false, 3},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newPayloadTree(t1, tt.keys)
+			actual, inserted := t.GetOrInsert(tt.item)
+			if actual != tt.wantActual || inserted != tt.wantInserted {
+				t1.Errorf("GetOrInsert() = %v, %v, want %v, %v", actual, inserted, tt.wantActual, tt.wantInserted)
+			}
+			if got := storedItem(t, tt.item.key); got != tt.wantActual {
+				t1.Errorf("stored item = %v, want %v", got, tt.wantActual)
+			}
+			if t.Len() != tt.wantLen {
+				t1.Errorf("Len() = %v, want %v", t.Len(), tt.wantLen)
+			}
+			if err := t.Validate(); err != nil {
+				t1.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTree_Update(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []int
+		k       int
+		f       func(p payload) payload
+		want    payload
+		wantErr error
+	}{
+		{"Empty Tree", []int{}, 1, func(p payload) payload { return p }, payload{}, redblack.KeyDoesNotExistError},
+		{"Missing Key", []int{1, 3}, 2, func(p payload) payload { return p }, payload{}, redblack.KeyDoesNotExistError},
+		{"Existing Key", []int{1, 2, 3}, 2, func(p payload) payload { return payload{p.key, p.data + "!"} }, payload{2, "2!"}, nil},
+		{"Changed Key", []int{1, 2, 3}, 2, func(p payload) payload { return payload{5, p.data} }, payload{2, "2"}, redblack.KeyChangedError},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newPayloadTree(t1, tt.keys)
+			if err := t.Update(tt.k, tt.f); err != tt.wantErr {
+				t1.Errorf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := storedItem(t, tt.k); got != tt.want {
+				t1.Errorf("stored item = %v, want %v", got, tt.want)
+			}
+			if t.Len() != len(tt.keys) {
+				t1.Errorf("Len() = %v, want %v", t.Len(), len(tt.keys))
+			}
+		})
+	}
+}
+
+func TestTree_UpsertAfterSnapshot(t1 *testing.T) {
+	keys := rand.Perm(100)
+	t := newPayloadTree(t1, keys)
+	p := t.Snapshot()
+	for _, k := range keys {
+		if k%2 == 0 {
+			t.ReplaceOrInsert(payload{k, "replaced"})
+		} else {
+			t.Update(k, func(p payload) payload { return payload{k, "updated"} })
+		}
+		t.ReplaceOrInsert(payload{k + 100, "new"})
+	}
+	if err := t.Validate(); err != nil {
+		t1.Errorf("Validate() error = %v", err)
+	}
+	if t.Len() != 200 {
+		t1.Errorf("Len() = %v, want %v", t.Len(), 200)
+	}
+	old := p.Tree()
+	for _, k := range keys {
+		if got, want := storedItem(old, k), (payload{k, strconv.Itoa(k)}); got != want {
+			t1.Fatalf("snapshot item = %v, want %v", got, want)
+		}
+	}
+}
+
 func TestTree_Delete(t1 *testing.T) {
 	tests := []struct {
 		name    string
@@ -187,6 +400,9 @@ func TestTree_Delete(t1 *testing.T) {
 			if !redblack.CheckLeftLeaning(t) {
 				t1.Errorf("Delete() resulted in a right-leaning tree")
 			}
+			if !redblack.CheckSize(t) {
+				t1.Errorf("Delete() resulted in wrong subtree sizes")
+			}
 		})
 	}
 }
@@ -233,6 +449,234 @@ func TestTree_DeleteMin(t1 *testing.T) {
 			if !redblack.CheckLeftLeaning(t) {
 				t1.Errorf("Delete() resulted in a right-leaning tree")
 			}
+			if !redblack.CheckSize(t) {
+				t1.Errorf("Delete() resulted in wrong subtree sizes")
+			}
+		})
+	}
+}
+
+func TestTree_DeleteMax(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   []int
+	}{
+		{"Empty Tree", []int{}, []int{}},
+		{"One Element", []int{1}, []int{}},
+		{"Two Elements", []int{1, 2}, []int{1}},
+		{"Tree Elements", []int{1, 2, 3}, []int{1, 2}},
+		{"Four Elements", []int{1, 2, 3, 4}, []int{1, 2, 3}},
+		{"Five Elements", []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4}},
+		{"Negative Elements", []int{-1, -2, -3, -4}, []int{-4, -3, -2}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newIntTree(t1, tt.values)
+			t.DeleteMax()
+
+			if !reflect.DeepEqual(t.ToSortedSlice(), tt.want) {
+				t1.Errorf("DeleteMax() = %v, want %v", t.ToSortedSlice(), tt.want)
+			}
+			if t.Len() != len(tt.want) {
+				t1.Errorf("Len() = %v, want %v", t.Len(), len(tt.want))
+			}
+			if err := t.Validate(); err != nil {
+				t1.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTree_PopMinMax(t1 *testing.T) {
+	values := rand.Perm(500)
+	t := newIntTree(t1, values)
+	want := slices.Sorted(slices.Values(values))
+	for len(want) > 0 {
+		var got, wantKey int
+		var ok bool
+		if rand.Intn(2) == 0 {
+			got, ok = t.PopMin()
+			wantKey, want = want[0], want[1:]
+		} else {
+			got, ok = t.PopMax()
+			wantKey, want = want[len(want)-1], want[:len(want)-1]
+		}
+		if !ok || got != wantKey {
+			t1.Fatalf("Pop() = %v, %v, want %v, true", got, ok, wantKey)
+		}
+		if t.Len() != len(want) {
+			t1.Fatalf("Len() = %v, want %v", t.Len(), len(want))
+		}
+		if err := t.Validate(); err != nil {
+			t1.Fatalf("Validate() error = %v", err)
+		}
+	}
+	if v, ok := t.PopMin(); ok {
+		t1.Errorf("PopMin() on empty tree = %v, true", v)
+	}
+	if v, ok := t.PopMax(); ok {
+		t1.Errorf("PopMax() on empty tree = %v, true", v)
+	}
+}
+
+func TestTree_Remove(t1 *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []int
+		k         int
+		want      payload
+		wantFound bool
+	}{
+		{"Empty Tree", []int{}, 1, payload{}, false},
+		{"Missing Key", []int{1, 3, 5}, 2, payload{}, false},
+		{"Below Min", []int{1, 3, 5}, 0, payload{}, false},
+		{"Root", []int{1, 2, 3}, 2, payload{2, "2"}, true},
+		{"Leaf", []int{1, 2, 3, 4, 5}, 5, payload{5, "5"}, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := newPayloadTree(t1, tt.keys)
+			item, found := t.Remove(tt.k)
+			if item != tt.want || found != tt.wantFound {
+				t1.Errorf("Remove() = %v, %v, want %v, %v", item, found, tt.want, tt.wantFound)
+			}
+			wantLen := len(tt.keys)
+			if tt.wantFound {
+				wantLen--
+			}
+			if t.Len() != wantLen {
+				t1.Errorf("Len() = %v, want %v", t.Len(), wantLen)
+			}
+			if err := t.Validate(); err != nil {
+				t1.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTree_DeleteMovesSuccessor(t1 *testing.T) {
+	// deleting a key whose node has a right child moves the item of the successor into that node, which keeps
+	// its place in the tree; the subtree metadata of the node is then recomputed on the way back up
+	t := new(redblack.Tree[int, redblack.Orderable[int]])
+	for i := 1; i <= 15; i++ {
+		if err := t.Insert(redblack.Ordered(i)); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+	}
+	n := t.GetTreeLevels()[0][0]
+	k := n.Value()
+	if !t.Delete(k) {
+		t1.Fatalf("Delete(%d) = false, want true", k)
+	}
+	if got := redblack.NodeOf(t, k+1); got != n {
+		t1.Errorf("the successor %d is stored in another node than the deleted key %d", k+1, k)
+	}
+	if err := t.Validate(); err != nil {
+		t1.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestTree_DeleteMissing(t1 *testing.T) {
+	// only even keys are in the tree, so deleting odd keys always misses
+	keys := make([]int, 0, 300)
+	for _, v := range rand.Perm(300) {
+		keys = append(keys, 2*v)
+	}
+	t := newIntTree(t1, keys)
+	want := t.ToSortedSlice()
+	for i := 0; i < 2000; i++ {
+		k := 2*rand.Intn(302) - 3
+		if t.Delete(k) {
+			t1.Fatalf("Delete(%v) = true, want false", k)
+		}
+		if err := t.Validate(); err != nil {
+			t1.Fatalf("Validate() after Delete(%v) error = %v", k, err)
+		}
+	}
+	if got := t.ToSortedSlice(); !reflect.DeepEqual(got, want) {
+		t1.Errorf("ToSortedSlice() = %v, want %v", got, want)
+	}
+}
+
+func TestTree_Empty(t1 *testing.T) {
+	trees := []struct {
+		name string
+		t    *intTree
+	}{
+		{"Zero Value", new(intTree)},
+		{"Emptied", newIntTree(t1, []int{1, 2, 3})},
+	}
+	trees[1].t.DeleteMin()
+	trees[1].t.DeleteMax()
+	trees[1].t.Delete(2)
+
+	for _, tt := range trees {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := tt.t
+			if v, ok := t.MinOk(); ok || v != 0 {
+				t1.Errorf("MinOk() = %v, %v, want 0, false", v, ok)
+			}
+			if v, ok := t.MaxOk(); ok || v != 0 {
+				t1.Errorf("MaxOk() = %v, %v, want 0, false", v, ok)
+			}
+			if v := t.Min(); v != 0 {
+				t1.Errorf("Min() = %v, want 0", v)
+			}
+			if v := t.Max(); v != 0 {
+				t1.Errorf("Max() = %v, want 0", v)
+			}
+			if h := t.Height(); h != 0 {
+				t1.Errorf("Height() = %v, want 0", h)
+			}
+			if h := t.BlackHeight(); h != 0 {
+				t1.Errorf("BlackHeight() = %v, want 0", h)
+			}
+			if d := t.MinDepth(); d != 0 {
+				t1.Errorf("MinDepth() = %v, want 0", d)
+			}
+			if levels := t.GetTreeLevels(); len(levels) != 0 {
+				t1.Errorf("GetTreeLevels() = %v, want no levels", levels)
+			}
+			if s := t.String(); s != "" {
+				t1.Errorf("String() = %q, want \"\"", s)
+			}
+			var sb strings.Builder
+			if err := t.Fprint(&sb, redblack.PrintOptions[int]{ASCII: true, MaxDepth: 2}); err != nil || sb.Len() != 0 {
+				t1.Errorf("Fprint() = %q, %v, want \"\", nil", sb.String(), err)
+			}
+			if _, err := t.Select(0); err != redblack.IndexOutOfRangeError {
+				t1.Errorf("Select() error = %v, want %v", err, redblack.IndexOutOfRangeError)
+			}
+			if _, err := t.SearchUpper(0); err != redblack.KeyDoesNotExistError {
+				t1.Errorf("SearchUpper() error = %v, want %v", err, redblack.KeyDoesNotExistError)
+			}
+			if _, err := t.SearchLower(0); err != redblack.KeyDoesNotExistError {
+				t1.Errorf("SearchLower() error = %v, want %v", err, redblack.KeyDoesNotExistError)
+			}
+			if r := t.Rank(1); r != 0 {
+				t1.Errorf("Rank() = %v, want 0", r)
+			}
+			if keys := slices.Collect(t.Sorted()); len(keys) != 0 {
+				t1.Errorf("Sorted() = %v, want no keys", keys)
+			}
+			if keys := slices.Collect(t.Backward()); len(keys) != 0 {
+				t1.Errorf("Backward() = %v, want no keys", keys)
+			}
+			for _, order := range []redblack.WalkOrder{redblack.INORDER, redblack.PREORDER, redblack.POSTORDER, redblack.LEVELORDER, redblack.REVERSE_INORDER} {
+				t.Walk(func(n *redblack.Node[int, redblack.Orderable[int]]) bool {
+					if n != nil {
+						t1.Errorf("Walk(%v) visits %v", order, n.Value())
+					}
+					return true
+				}, order)
+			}
+			if c := t.Cursor(); c.First() || c.Last() || c.Valid() {
+				t1.Errorf("Cursor() is valid on an empty tree")
+			}
+			if err := t.Validate(); err != nil {
+				t1.Errorf("Validate() error = %v", err)
+			}
 		})
 	}
 }
@@ -276,6 +720,40 @@ func TestTree_ToSortedSlice(t1 *testing.T) {
 	}
 }
 
+func TestTree_HeightMetadata(t1 *testing.T) {
+	t := new(intTree)
+	check := func() {
+		t1.Helper()
+		height, minDepth, blackHeight := redblack.Depths(t)
+		if got := t.Height(); got != height {
+			t1.Fatalf("Height() = %v, want %v", got, height)
+		}
+		if got := t.MinDepth(); got != minDepth {
+			t1.Fatalf("MinDepth() = %v, want %v", got, minDepth)
+		}
+		if got := t.BlackHeight(); got != blackHeight {
+			t1.Fatalf("BlackHeight() = %v, want %v", got, blackHeight)
+		}
+	}
+	check()
+	for i := 0; i < 2000; i++ {
+		k := rand.Intn(300)
+		switch found, _ := t.Search(k); {
+		case i%50 == 0:
+			t.DeleteMin()
+		case found:
+			t.Delete(k)
+		default:
+			t.Insert(redblack.Ordered(k))
+		}
+		check()
+	}
+	for t.Len() > 0 {
+		t.DeleteMin()
+		check()
+	}
+}
+
 func TestTree_checkBlackHeight(t1 *testing.T) {
 	tests := []struct {
 		name   string
@@ -438,6 +916,7 @@ func TestTree_Walk(t1 *testing.T) {
 		want   [][]int
 	}{
 		{"IN order", []int{1, 2, 3, 4, 5}, redblack.INORDER, [][]int{{1, 2, 3, 4, 5}}},
+		{"REVERSE IN order", []int{1, 2, 3, 4, 5}, redblack.REVERSE_INORDER, [][]int{{5, 4, 3, 2, 1}}},
 		{"PRE order", []int{1, 2, 3, 4, 5}, redblack.PREORDER, [][]int{{3, 2, 1, 5, 4}, {2, 1, 4, 3, 5}, {4, 2, 1, 3, 5}}},
 		{"POST order", []int{1, 2, 3, 4, 5}, redblack.POSTORDER, [][]int{{1, 2, 4, 5, 3}, {1, 3, 2, 5, 4}, {1, 3, 5, 4, 2}}},
 	}
@@ -517,3 +996,395 @@ func TestTree_Sorted(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTree_Select(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		i       int
+		want    int
+		wantErr bool
+	}{
+		{"Empty Tree", []int{}, 0, 0, true},
+		{"First", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 0, 1, false},
+		{"Middle", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 4, 14, false},
+		{"Last", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 8, 67, false},
+		{"Out of Range", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 9, 0, true},
+		{"Negative Index", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, -1, 0, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			rand.Shuffle(len(tt.values), func(i, j int) {
+				tt.values[i], tt.values[j] = tt.values[j], tt.values[i]
+			})
+			vals := make([]redblack.Orderable[int], 0, len(tt.values))
+			for _, v := range tt.values {
+				vals = append(vals, redblack.Ordered(v))
+			}
+
+			t, err := redblack.NewTree(vals, false)
+			if err != nil {
+				t1.Errorf("redblack.NewTree() error = %v", err)
+				return
+			}
+			got, err := t.Select(tt.i)
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("Select() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t1.Errorf("Select() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_Rank(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		k      int
+		want   int
+	}{
+		{"Empty Tree", []int{}, 1, 0},
+		{"Below Min", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, -10, 0},
+		{"Existing Key", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 14, 4},
+		{"Missing Key", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 10, 4},
+		{"Above Max", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 100, 9},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			rand.Shuffle(len(tt.values), func(i, j int) {
+				tt.values[i], tt.values[j] = tt.values[j], tt.values[i]
+			})
+			vals := make([]redblack.Orderable[int], 0, len(tt.values))
+			for _, v := range tt.values {
+				vals = append(vals, redblack.Ordered(v))
+			}
+
+			t, err := redblack.NewTree(vals, false)
+			if err != nil {
+				t1.Errorf("redblack.NewTree() error = %v", err)
+				return
+			}
+			if got := t.Rank(tt.k); got != tt.want {
+				t1.Errorf("Rank() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_DeleteAt(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		i       int
+		deleted int
+		want    []int
+		wantErr bool
+	}{
+		{"Empty Tree", []int{}, 0, 0, []int{}, true},
+		{"One Element", []int{1}, 0, 1, []int{}, false},
+		{"Five Elements", []int{1, 2, 3, 4, 5}, 3, 4, []int{1, 2, 3, 5}, false},
+		{"Out of Range", []int{1, 2, 3}, 3, 0, []int{1, 2, 3}, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			rand.Shuffle(len(tt.values), func(i, j int) {
+				tt.values[i], tt.values[j] = tt.values[j], tt.values[i]
+			})
+			vals := make([]redblack.Orderable[int], 0, len(tt.values))
+			for _, v := range tt.values {
+				vals = append(vals, redblack.Ordered(v))
+			}
+
+			t, err := redblack.NewTree(vals, false)
+			if err != nil {
+				t1.Errorf("redblack.NewTree() error = %v", err)
+				return
+			}
+			got, err := t.DeleteAt(tt.i)
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("DeleteAt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.deleted {
+				t1.Errorf("DeleteAt() got = %v, want %v", got, tt.deleted)
+			}
+			if !reflect.DeepEqual(t.ToSortedSlice(), tt.want) {
+				t1.Errorf("DeleteAt() = %v, want %v", t.ToSortedSlice(), tt.want)
+			}
+			if !redblack.CheckSize(t) {
+				t1.Errorf("DeleteAt() resulted in wrong subtree sizes")
+			}
+		})
+	}
+}
+
+func TestTree_CountRange(t1 *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		lo, hi int
+		want   int
+	}{
+		{"Empty Tree", []int{}, 0, 10, 0},
+		{"All Keys", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 0, 100, 9},
+		{"Existing Bounds", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 5, 44, 4},
+		{"Missing Bounds", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 3, 45, 5},
+		{"Empty Range", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 9, 13, 0},
+		{"Inverted Range", []int{1, 2, 5, 8, 14, 23, 44, 50, 67}, 44, 5, 0},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			rand.Shuffle(len(tt.values), func(i, j int) {
+				tt.values[i], tt.values[j] = tt.values[j], tt.values[i]
+			})
+			vals := make([]redblack.Orderable[int], 0, len(tt.values))
+			for _, v := range tt.values {
+				vals = append(vals, redblack.Ordered(v))
+			}
+
+			t, err := redblack.NewTree(vals, false)
+			if err != nil {
+				t1.Errorf("redblack.NewTree() error = %v", err)
+				return
+			}
+			if got := t.CountRange(tt.lo, tt.hi); got != tt.want {
+				t1.Errorf("CountRange() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_RandomOperations(t1 *testing.T) {
+	t := new(redblack.Tree[int, redblack.Orderable[int]])
+	want := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(256)
+		switch rand.Intn(3) {
+		case 0:
+			if !want[k] {
+				if err := t.Insert(redblack.Ordered(k)); err != nil {
+					t1.Fatalf("Insert() error = %v", err)
+				}
+				want[k] = true
+			}
+		case 1:
+			if success := t.Delete(k); success != want[k] {
+				t1.Fatalf("Delete() = %v, want %v", success, want[k])
+			}
+			delete(want, k)
+		case 2:
+			if t.Len() > 0 {
+				v, err := t.DeleteAt(rand.Intn(t.Len()))
+				if err != nil {
+					t1.Fatalf("DeleteAt() error = %v", err)
+				}
+				delete(want, v)
+			}
+		}
+
+		if t.Len() != len(want) {
+			t1.Fatalf("Len() = %v, want %v", t.Len(), len(want))
+		}
+		if !redblack.CheckNoRedRed(t) {
+			t1.Fatalf("operation %d resulted in red-red nodes", i)
+		}
+		if _, ok := redblack.CheckBlackHeight(t); !ok {
+			t1.Fatalf("operation %d resulted in different black-heights", i)
+		}
+		if !redblack.CheckLeftLeaning(t) {
+			t1.Fatalf("operation %d resulted in a right-leaning tree", i)
+		}
+		if !redblack.CheckSize(t) {
+			t1.Fatalf("operation %d resulted in wrong subtree sizes", i)
+		}
+	}
+}
+
+func TestTree_Range(t1 *testing.T) {
+	values := []int{1, 2, 5, 8, 14, 23, 44, 50, 67}
+	tests := []struct {
+		name       string
+		values     []int
+		rangeFunc  func(t *intTree) iter.Seq[int]
+		breakAfter int
+		want       []int
+	}{
+		{"Empty Tree", []int{}, func(t *intTree) iter.Seq[int] { return t.Range(0, 10) }, -1, []int{}},
+		{"Range", values, func(t *intTree) iter.Seq[int] { return t.Range(5, 44) }, -1, []int{5, 8, 14, 23}},
+		{"Range Missing Bounds", values, func(t *intTree) iter.Seq[int] { return t.Range(3, 45) }, -1, []int{5, 8, 14, 23, 44}},
+		{"Range Empty", values, func(t *intTree) iter.Seq[int] { return t.Range(9, 13) }, -1, []int{}},
+		{"Range Break", values, func(t *intTree) iter.Seq[int] { return t.Range(2, 50) }, 8, []int{2, 5, 8}},
+		{"RangeClosed", values, func(t *intTree) iter.Seq[int] { return t.RangeClosed(5, 44) }, -1, []int{5, 8, 14, 23, 44}},
+		{"RangeFrom", values, func(t *intTree) iter.Seq[int] { return t.RangeFrom(23) }, -1, []int{23, 44, 50, 67}},
+		{"RangeTo", values, func(t *intTree) iter.Seq[int] { return t.RangeTo(8) }, -1, []int{1, 2, 5}},
+		{"Backward", values, func(t *intTree) iter.Seq[int] { return t.Backward() }, 14, []int{67, 50, 44, 23, 14}},
+		{"RangeBackward", values, func(t *intTree) iter.Seq[int] { return t.RangeBackward(5, 44) }, -1, []int{23, 14, 8, 5}},
+		{"RangeBackward Break", values, func(t *intTree) iter.Seq[int] { return t.RangeBackward(2, 50) }, 14, []int{44, 23, 14}},
+		{"RangeClosedBackward", values, func(t *intTree) iter.Seq[int] { return t.RangeClosedBackward(5, 44) }, -1, []int{44, 23, 14, 8, 5}},
+		{"RangeFromBackward", values, func(t *intTree) iter.Seq[int] { return t.RangeFromBackward(23) }, -1, []int{67, 50, 44, 23}},
+		{"RangeToBackward", values, func(t *intTree) iter.Seq[int] { return t.RangeToBackward(8) }, -1, []int{5, 2, 1}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			vals := make([]redblack.Orderable[int], 0, len(tt.values))
+			for _, v := range tt.values {
+				vals = append(vals, redblack.Ordered(v))
+			}
+			rand.Shuffle(len(vals), func(i, j int) {
+				vals[i], vals[j] = vals[j], vals[i]
+			})
+
+			t, err := redblack.NewTree(vals, false)
+			if err != nil {
+				t1.Errorf("redblack.NewTree() error = %v", err)
+				return
+			}
+
+			got := make([]int, 0, len(tt.values))
+			for v := range tt.rangeFunc(t) {
+				got = append(got, v)
+				if v == tt.breakAfter {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_NewTreeFromSorted(t1 *testing.T) {
+	tests := []struct {
+		name              string
+		values            []int
+		ignore_duplicates bool
+		want              []int
+		wantErr           error
+	}{
+		{"Empty", []int{}, false, []int{}, nil},
+		{"One Element", []int{1}, false, []int{1}, nil},
+		{"Successful", []int{1, 2, 3, 4, 5, 6, 7, 8}, false, []int{1, 2, 3, 4, 5, 6, 7, 8}, nil},
+		{"Complete", []int{1, 2, 3, 4, 5, 6, 7}, false, []int{1, 2, 3, 4, 5, 6, 7}, nil},
+		{"Duplicate", []int{1, 2, 3, 3, 4}, false, nil, redblack.KeyExistsError},
+		{"Duplicate Ignore", []int{1, 1, 2, 3, 3, 3, 4}, true, []int{1, 2, 3, 4}, nil},
+		{"Not Sorted", []int{1, 3, 2, 4}, false, nil, redblack.NotSortedError},
+	}
+	for n := 9; n <= 130; n += 11 {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = 2 * i
+		}
+		tests = append(tests, struct {
+			name              string
+			values            []int
+			ignore_duplicates bool
+			want              []int
+			wantErr           error
+		}{fmt.Sprintf("%d Elements", n), values, false, values, nil})
+	}
+
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			vals := make([]redblack.Orderable[int], 0, len(tt.values))
+			for _, v := range tt.values {
+				vals = append(vals, redblack.Ordered(v))
+			}
+			t, err := redblack.NewTreeFromSorted(vals, tt.ignore_duplicates)
+			if err != tt.wantErr {
+				t1.Fatalf("NewTreeFromSorted() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(t.ToSortedSlice(), tt.want) {
+				t1.Errorf("NewTreeFromSorted() = %v, want %v", t.ToSortedSlice(), tt.want)
+			}
+			if t.Len() != len(tt.want) {
+				t1.Errorf("Len() = %v, want %v", t.Len(), len(tt.want))
+			}
+			if !redblack.CheckNoRedRed(t) {
+				t1.Errorf("NewTreeFromSorted() resulted in red-red nodes")
+			}
+			if _, ok := redblack.CheckBlackHeight(t); !ok {
+				t1.Errorf("NewTreeFromSorted() resulted in different black-heights")
+			}
+			if !redblack.CheckLeftLeaning(t) {
+				t1.Errorf("NewTreeFromSorted() resulted in a right-leaning tree")
+			}
+			if !redblack.CheckSize(t) {
+				t1.Errorf("NewTreeFromSorted() resulted in wrong subtree sizes")
+			}
+
+			// the tree must support modifications
+			for _, v := range tt.want {
+				t.Delete(v)
+				if !redblack.CheckNoRedRed(t) || !redblack.CheckLeftLeaning(t) {
+					t1.Fatalf("Delete() after NewTreeFromSorted() resulted in an invalid tree")
+				}
+			}
+		})
+	}
+}
+
+func TestTree_FromSeq(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		want    []int
+		wantErr bool
+	}{
+		{"Sorted", []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}, false},
+		{"Not Sorted", []int{3, 1, 5, 2, 4}, []int{1, 2, 3, 4, 5}, false},
+		{"Duplicate", []int{3, 1, 3}, nil, true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			seq := func(yield func(redblack.Orderable[int]) bool) {
+				for _, v := range tt.values {
+					if !yield(redblack.Ordered(v)) {
+						return
+					}
+				}
+			}
+			t, err := redblack.FromSeq(seq, false)
+			if (err != nil) != tt.wantErr {
+				t1.Fatalf("FromSeq() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(t.ToSortedSlice(), tt.want) {
+				t1.Errorf("FromSeq() = %v, want %v", t.ToSortedSlice(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_InsertAllocs(t1 *testing.T) {
+	t := newPayloadTree(t1, rand.Perm(1024))
+	item := payload{key: 2048}
+	// only the new node is allocated, keeping the subtree metadata up to date must not allocate
+	allocs := testing.AllocsPerRun(100, func() {
+		t.Insert(item)
+		t.Delete(item.key)
+	})
+	if allocs > 1 {
+		t1.Errorf("Insert() and Delete() allocate %v times, want at most 1", allocs)
+	}
+}
+
+func BenchmarkTree_Insert(b *testing.B) {
+	keys := rand.Perm(1 << 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := new(redblack.Tree[int, payload])
+		for _, k := range keys {
+			t.Insert(payload{key: k})
+		}
+	}
+}
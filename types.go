@@ -1,6 +1,8 @@
 package redblack
 
 import (
+	"cmp"
+
 	"golang.org/x/exp/constraints"
 )
 
@@ -15,13 +17,7 @@ type ordered[T constraints.Ordered] struct {
 }
 
 func (o ordered[T]) CompareTo(other T) int {
-	if o.value == other {
-		return 0
-	}
-	if o.value < other {
-		return -1
-	}
-	return 1
+	return cmp.Compare(o.value, other)
 }
 
 func (o ordered[T]) Value() T {
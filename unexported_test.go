@@ -11,3 +11,48 @@ func CheckBlackHeight[V any, T Orderable[V]](t *Tree[V, T]) (uint, bool) {
 func CheckLeftLeaning[V any, T Orderable[V]](t *Tree[V, T]) bool {
 	return t.checkLeftLeaning()
 }
+
+func CheckSize[V any, T Orderable[V]](t *Tree[V, T]) bool {
+	return t.checkSize()
+}
+
+// Recolor flips the color of the node with the key k.
+func Recolor[V any, T Orderable[V]](t *Tree[V, T], k V) {
+	t.root.search(k).red = !t.root.search(k).red
+}
+
+// NodeOf returns the node with the key k, nil if k is not in the tree.
+func NodeOf[V any, T Orderable[V]](t *Tree[V, T], k V) *Node[V, T] {
+	return t.root.search(k)
+}
+
+// SetItem replaces the item of the node with the key k without moving the node.
+func SetItem[V any, T Orderable[V]](t *Tree[V, T], k V, item T) {
+	t.root.search(k).value = item
+}
+
+// SetLen overwrites the stored number of nodes.
+func SetLen[V any, T Orderable[V]](t *Tree[V, T], n int) {
+	t.num = n
+}
+
+// Depths computes the height, the minimum depth and the black height of the tree by walking all nodes.
+func Depths[V any, T Orderable[V]](t *Tree[V, T]) (height, minDepth, blackHeight int) {
+	var walk func(n *Node[V, T]) (int, int, int)
+	walk = func(n *Node[V, T]) (int, int, int) {
+		if n == nil {
+			return 0, 0, 0
+		}
+		lh, lm, lb := walk(n.left)
+		rh, rm, _ := walk(n.right)
+		m := min(lm, rm)
+		if n.left == nil || n.right == nil {
+			m = max(lm, rm)
+		}
+		if !n.red {
+			lb++
+		}
+		return max(lh, rh) + 1, m + 1, lb
+	}
+	return walk(t.root)
+}
@@ -0,0 +1,111 @@
+package redblack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Invariant is a structural property of a left-leaning red-black tree that is checked by Validate.
+type Invariant string
+
+const (
+	InvariantBlackRoot   Invariant = "black root"
+	InvariantNoRedRed    Invariant = "no red node with a red child"
+	InvariantBlackHeight Invariant = "equal black height"
+	InvariantLeftLeaning Invariant = "left-leaning red links"
+	InvariantOrder       Invariant = "ascending key order"
+	InvariantSize        Invariant = "subtree size"
+	InvariantHeight      Invariant = "subtree height"
+	InvariantLen         Invariant = "length matches node count"
+)
+
+// ValidationError describes a violation of an invariant found by Validate.
+type ValidationError[V any] struct {
+	Invariant Invariant
+	// Key is the key of the node at which the invariant is violated.
+	// It is the zero value for InvariantLen, which concerns the whole tree.
+	Key V
+	// Path leads from the root to the node, "L" and "R" stand for the left and the right child.
+	// The path of the root is empty.
+	Path string
+	// Detail explains the violation.
+	Detail string
+}
+
+func (e *ValidationError[V]) Error() string {
+	if e.Invariant == InvariantLen {
+		return fmt.Sprintf("invariant %q violated: %s", e.Invariant, e.Detail)
+	}
+	path := e.Path
+	if path == "" {
+		path = "root"
+	}
+	return fmt.Sprintf("invariant %q violated at key %v (path %s): %s", e.Invariant, e.Key, path, e.Detail)
+}
+
+// Validate checks the structure of the tree: the root is black, no red node has a red child, all paths
+// from the root to a leaf have the same number of black nodes, red links lean left, the keys are in ascending
+// order, the stored subtree sizes and heights match the actual subtrees, and the length of the tree matches
+// the actual node count.
+// Returns nil if the tree is valid. Otherwise all violations are returned as *ValidationError[V] values
+// joined by errors.Join, so that they can be inspected with errors.As or by unwrapping the error.
+func (t *Tree[V, T]) Validate() error {
+	var errs []error
+	report := func(inv Invariant, n *Node[V, T], path []byte, format string, args ...any) {
+		e := &ValidationError[V]{Invariant: inv, Path: string(path), Detail: fmt.Sprintf(format, args...)}
+		if n != nil {
+			e.Key = n.Value()
+		}
+		errs = append(errs, e)
+	}
+
+	// validate checks the subtree of n, whose keys have to be between the keys of lo and hi if they are not nil.
+	// Returns the black height, the number of nodes, the height and the minimum depth of the subtree.
+	var validate func(n, lo, hi *Node[V, T], path []byte) (int, int, int, int)
+	validate = func(n, lo, hi *Node[V, T], path []byte) (int, int, int, int) {
+		if n == nil {
+			return 0, 0, 0, 0
+		}
+		if lo != nil && n.value.CompareTo(lo.Value()) <= 0 {
+			report(InvariantOrder, n, path, "key is not greater than %v", lo.Value())
+		}
+		if hi != nil && n.value.CompareTo(hi.Value()) >= 0 {
+			report(InvariantOrder, n, path, "key is not less than %v", hi.Value())
+		}
+		if n.red && (isRed(n.left) || isRed(n.right)) {
+			report(InvariantNoRedRed, n, path, "red node has a red child")
+		}
+		if isRed(n.right) && !isRed(n.left) {
+			report(InvariantLeftLeaning, n, path, "red right child without a red left sibling")
+		}
+
+		lh, ln, lHeight, lMin := validate(n.left, lo, n, append(path, 'L'))
+		rh, rn, rHeight, rMin := validate(n.right, n, hi, append(path, 'R'))
+		if lh != rh {
+			report(InvariantBlackHeight, n, path, "left subtree has black height %d, right subtree %d", lh, rh)
+		}
+		if n.size != ln+rn+1 {
+			report(InvariantSize, n, path, "stored size is %d, subtree has %d nodes", n.size, ln+rn+1)
+		}
+		h, minDepth := max(lHeight, rHeight)+1, min(lMin, rMin)+1
+		if n.left == nil || n.right == nil {
+			minDepth = max(lMin, rMin) + 1
+		}
+		if int(n.height) != h || int(n.minDepth) != minDepth {
+			report(InvariantHeight, n, path, "stored height and minimum depth are %d and %d, subtree has %d and %d",
+				n.height, n.minDepth, h, minDepth)
+		}
+		if !n.red {
+			lh++
+		}
+		return max(lh, rh), ln + rn + 1, h, minDepth
+	}
+
+	if isRed(t.root) {
+		report(InvariantBlackRoot, t.root, nil, "root is red")
+	}
+	if _, count, _, _ := validate(t.root, nil, nil, make([]byte, 0, 64)); count != t.num {
+		report(InvariantLen, nil, nil, "Len() is %d, tree has %d nodes", t.num, count)
+	}
+	return errors.Join(errs...)
+}
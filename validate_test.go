@@ -0,0 +1,89 @@
+package redblack_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/gregorgebhardt/redblack"
+)
+
+func TestTree_Validate(t1 *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		corrupt func(t *intTree)
+		want    []redblack.ValidationError[int]
+	}{
+		{"Empty Tree", []int{}, func(t *intTree) {}, nil},
+		{"Valid Tree", rand.Perm(100), func(t *intTree) {}, nil},
+		{"Red Root", []int{2, 1, 3}, func(t *intTree) { redblack.Recolor(t, 2) }, []redblack.ValidationError[int]{
+			{Invariant: redblack.InvariantBlackRoot, Key: 2, Path: ""},
+			{Invariant: redblack.InvariantNoRedRed, Key: 2, Path: ""},
+		}},
+		{"Black Height", []int{2, 1, 3}, func(t *intTree) { redblack.Recolor(t, 3) }, []redblack.ValidationError[int]{
+			{Invariant: redblack.InvariantBlackHeight, Key: 2, Path: ""},
+		}},
+		{"Right-Leaning", []int{2, 1, 3}, func(t *intTree) { redblack.Recolor(t, 1) }, []redblack.ValidationError[int]{
+			{Invariant: redblack.InvariantLeftLeaning, Key: 2, Path: ""},
+			{Invariant: redblack.InvariantBlackHeight, Key: 2, Path: ""},
+		}},
+		{"Order", []int{2, 1, 3}, func(t *intTree) { redblack.SetItem(t, 3, redblack.Orderable[int](redblack.Ordered(0))) }, []redblack.ValidationError[int]{
+			{Invariant: redblack.InvariantOrder, Key: 0, Path: "R"},
+		}},
+		{"Len", []int{1, 2, 3}, func(t *intTree) { redblack.SetLen(t, 4) }, []redblack.ValidationError[int]{
+			{Invariant: redblack.InvariantLen},
+		}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := new(intTree)
+			for _, v := range tt.values {
+				if err := t.Insert(redblack.Ordered(v)); err != nil {
+					t1.Fatalf("Insert() error = %v", err)
+				}
+			}
+			tt.corrupt(t)
+			err := t.Validate()
+			if (err != nil) != (tt.want != nil) {
+				t1.Fatalf("Validate() error = %v, want %v", err, tt.want)
+			}
+			if err == nil {
+				return
+			}
+			got := err.(interface{ Unwrap() []error }).Unwrap()
+			if len(got) != len(tt.want) {
+				t1.Fatalf("Validate() error = %v, want %d violations", err, len(tt.want))
+			}
+			for i, e := range got {
+				var ve *redblack.ValidationError[int]
+				if !errors.As(e, &ve) {
+					t1.Fatalf("Validate() error %v is not a ValidationError", e)
+				}
+				if ve.Invariant != tt.want[i].Invariant || ve.Key != tt.want[i].Key || ve.Path != tt.want[i].Path {
+					t1.Errorf("Validate() violation = %+v, want %+v", *ve, tt.want[i])
+				}
+				if ve.Detail == "" {
+					t1.Errorf("Validate() violation %+v has no detail", *ve)
+				}
+			}
+		})
+	}
+}
+
+func TestTree_ValidateAfterOperations(t1 *testing.T) {
+	t := new(intTree)
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(500)
+		if found, _ := t.Search(k); found {
+			t.Delete(k)
+		} else if err := t.Insert(redblack.Ordered(k)); err != nil {
+			t1.Fatalf("Insert() error = %v", err)
+		}
+		if i%100 == 0 {
+			if err := t.Validate(); err != nil {
+				t1.Fatalf("Validate() error = %v", err)
+			}
+		}
+	}
+}